@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"crypto/x509"
+	"net/http"
+
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+// RequireClientCert rejects requests without a verified mutual TLS client
+// certificate (see jimohttp.Context.ClientCert) with 401, for service-to-service
+// routes. verify, if not nil, must also accept the certificate, e.g. by checking
+// its subject:
+//
+//	auth.RequireClientCert(func(cert *x509.Certificate) bool {
+//		return cert.Subject.CommonName == "billing-service"
+//	})
+//
+// The server's tls.Config must request client certificates and set ClientCAs.
+func RequireClientCert(verify func(*x509.Certificate) bool) jimohttp.Middleware {
+	return func(next jimohttp.HandlerFunc) jimohttp.HandlerFunc {
+		return func(ctx *jimohttp.Context) {
+			cert := ctx.ClientCert()
+			if cert == nil || (verify != nil && !verify(cert)) {
+				panic(jimohttp.HTTPError{Status: http.StatusUnauthorized, Message: "Unauthenticated"})
+			}
+			next(ctx)
+		}
+	}
+}
@@ -1,22 +1,70 @@
 package auth
 
-import "strconv"
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+)
 
+// ParseUserID converts a raw session user id (see ID) into an int.
+//
+// Session values round-trip through JSON, so an int stored by Login comes back as
+// float64, or as json.Number with UseJSONNumber. ParseUserID accepts the int
+// types, whole float64s, json.Numbers and decimal strings, like Session.GetInt;
+// fractions and values out of range are rejected.
 func ParseUserID(v any) (int, bool) {
+	n, ok := parseUserID64(v)
+	if !ok || int64(int(n)) != n {
+		return 0, false
+	}
+	return int(n), true
+}
+
+func parseUserID64(v any) (int64, bool) {
 	switch x := v.(type) {
 	case int:
-		return x, true
+		return int64(x), true
+	case int32:
+		return int64(x), true
 	case int64:
-		return int(x), true
+		return x, true
 	case float64:
-		return int(x), true
-	case string:
-		n, err := strconv.Atoi(x)
-		if err != nil {
+		if x != math.Trunc(x) || x < math.MinInt64 || x >= math.MaxInt64 {
 			return 0, false
 		}
-		return n, true
+		return int64(x), true
+	case json.Number:
+		n, err := x.Int64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseInt(x, 10, 64)
+		return n, err == nil
 	default:
 		return 0, false
 	}
 }
+
+// convertUserID converts a raw session user id into the id type of an Authenticate
+// loader: K itself, int, int64 (see ParseUserID) or string.
+func convertUserID[K any](v any) (K, bool) {
+	if id, ok := v.(K); ok {
+		return id, true
+	}
+	var id K
+	switch p := any(&id).(type) {
+	case *int:
+		n, ok := ParseUserID(v)
+		*p = n
+		return id, ok
+	case *int64:
+		n, ok := parseUserID64(v)
+		*p = n
+		return id, ok
+	case *string:
+		if n, ok := parseUserID64(v); ok {
+			*p = strconv.FormatInt(n, 10)
+			return id, true
+		}
+	}
+	return id, false
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseUserID(t *testing.T) {
+	for _, tc := range []struct {
+		in   any
+		want int
+		ok   bool
+	}{
+		{7, 7, true},
+		{int32(7), 7, true},
+		{int64(7), 7, true},
+		{float64(7), 7, true},
+		{json.Number("7"), 7, true},
+		{"7", 7, true},
+		{float64(7.5), 0, false},
+		{math.Inf(1), 0, false},
+		{json.Number("7.5"), 0, false},
+		{"7.5", 0, false},
+		{"abc", 0, false},
+		{true, 0, false},
+		{nil, 0, false},
+	} {
+		got, ok := ParseUserID(tc.in)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("ParseUserID(%#v) = %d, %v; want %d, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestConvertUserID(t *testing.T) {
+	if id, ok := convertUserID[int64](json.Number("9007199254740993")); !ok || id != 9007199254740993 {
+		t.Errorf("int64 from json.Number = %d, %v", id, ok)
+	}
+	if id, ok := convertUserID[string](float64(42)); !ok || id != "42" {
+		t.Errorf("string from float64 = %q, %v", id, ok)
+	}
+	if id, ok := convertUserID[string]("u-1"); !ok || id != "u-1" {
+		t.Errorf("string from string = %q, %v", id, ok)
+	}
+	if _, ok := convertUserID[string](float64(4.2)); ok {
+		t.Error("string from 4.2 converted")
+	}
+	if _, ok := convertUserID[int](float64(4.2)); ok {
+		t.Error("int from 4.2 converted")
+	}
+}
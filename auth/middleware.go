@@ -6,47 +6,148 @@ import (
 	jimohttp "github.com/jimo-go/framework/http"
 )
 
-const sessionUserIDKey = "auth.user_id"
+const (
+	sessionUserIDKey = "auth.user_id"
+	contextUserKey   = "auth.user"
+)
 
-func Login(ctx *jimohttp.Context, userID int) {
+// LoginID marks the session as authenticated as the user with the given id.
+//
+// Any id type the session can serialize works (int, string, UUID string, ...).
+func LoginID(ctx *jimohttp.Context, id any) {
 	s := ctx.Session()
 	if s == nil {
 		panic(jimohttp.HTTPError{Status: http.StatusInternalServerError, Message: "Session is not enabled"})
 	}
-	s.Put(sessionUserIDKey, userID)
+	s.Put(sessionUserIDKey, id)
+}
+
+// Login is LoginID for int user ids.
+func Login(ctx *jimohttp.Context, userID int) {
+	LoginID(ctx, userID)
 }
 
+// Logout removes the authenticated user from the session and the request context,
+// and regenerates the session to prevent fixation on the next login.
 func Logout(ctx *jimohttp.Context) {
+	ctx.Set(contextUserKey, nil)
 	s := ctx.Session()
 	if s == nil {
 		return
 	}
-	s.Put(sessionUserIDKey, nil)
+	s.Forget(sessionUserIDKey)
+	s.Regenerate()
 }
 
-func UserID(ctx *jimohttp.Context) (int, bool) {
+// ID returns the raw user id stored by LoginID.
+//
+// The value has been through the session's serialization, so numeric ids typically
+// come back as float64; use UserID (or Session.GetInt) for int ids.
+func ID(ctx *jimohttp.Context) (any, bool) {
 	s := ctx.Session()
 	if s == nil {
-		return 0, false
+		return nil, false
 	}
 	v := s.Get(sessionUserIDKey)
-	switch x := v.(type) {
-	case int:
-		return x, true
-	case float64:
-		return int(x), true
-	default:
-		return 0, false
+	if v == nil {
+		return nil, false
 	}
+	return v, true
+}
+
+// UserID returns the authenticated user id as an int.
+func UserID(ctx *jimohttp.Context) (int, bool) {
+	return ctx.Session().GetInt(sessionUserIDKey)
 }
 
 func RequireAuth() jimohttp.Middleware {
 	return func(next jimohttp.HandlerFunc) jimohttp.HandlerFunc {
 		return func(ctx *jimohttp.Context) {
-			if _, ok := UserID(ctx); !ok {
+			if _, ok := ID(ctx); !ok {
 				panic(jimohttp.HTTPError{Status: http.StatusUnauthorized, Message: "Unauthenticated"})
 			}
 			next(ctx)
 		}
 	}
 }
+
+type authenticateOptions struct {
+	logoutMissing bool
+	redirectTo    string
+}
+
+// AuthenticateOption configures Authenticate.
+type AuthenticateOption func(*authenticateOptions)
+
+// LogoutMissing logs the session out when the loader cannot find the user,
+// e.g. because the account was deleted after login.
+func LogoutMissing() AuthenticateOption {
+	return func(o *authenticateOptions) {
+		o.logoutMissing = true
+	}
+}
+
+// RedirectMissing logs out a session whose user cannot be found and redirects to path
+// (typically the login page). Requests to path itself are not redirected.
+func RedirectMissing(path string) AuthenticateOption {
+	return func(o *authenticateOptions) {
+		o.logoutMissing = true
+		o.redirectTo = path
+	}
+}
+
+// Authenticate loads the current user of an authenticated session via loader and
+// stores it on the request context, where User retrieves it.
+//
+// The loader's id type is the one passed to Login or LoginID: int and int64 ids are
+// converted back from their serialized form, string ids (such as UUIDs) are passed
+// as-is, and a loader taking any receives the raw value (see ID). A session whose id
+// cannot be converted is treated as a guest.
+//
+// Guests pass through untouched. When loader reports the user as missing, the request
+// continues as a guest-like request with no user; see LogoutMissing and RedirectMissing
+// to clear the phantom session instead.
+func Authenticate[K, U any](loader func(id K) (U, bool), opts ...AuthenticateOption) jimohttp.Middleware {
+	var o authenticateOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	return func(next jimohttp.HandlerFunc) jimohttp.HandlerFunc {
+		return func(ctx *jimohttp.Context) {
+			raw, ok := ID(ctx)
+			if !ok {
+				next(ctx)
+				return
+			}
+			id, ok := convertUserID[K](raw)
+			if !ok {
+				next(ctx)
+				return
+			}
+
+			if user, found := loader(id); found {
+				ctx.Set(contextUserKey, user)
+				next(ctx)
+				return
+			}
+
+			if o.logoutMissing {
+				Logout(ctx)
+			}
+			if o.redirectTo != "" && ctx.Request.URL.Path != o.redirectTo {
+				http.Redirect(ctx.ResponseWriter, ctx.Request, o.redirectTo, http.StatusFound)
+				return
+			}
+			next(ctx)
+		}
+	}
+}
+
+// User returns the user loaded by Authenticate for the current request.
+func User[U any](ctx *jimohttp.Context) (U, bool) {
+	user, ok := ctx.Get(contextUserKey).(U)
+	return user, ok
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+// loginAndLoad logs in with id on one request and returns what the loader received
+// on the next one.
+func loginAndLoad[K any](t *testing.T, id any) (K, bool) {
+	t.Helper()
+	sm, err := jimohttp.NewSessionManager("test-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got K
+	called := false
+	r := jimohttp.NewRouter()
+	r.Use(jimohttp.Sessions(sm))
+	r.Get("/login", func(c *jimohttp.Context) {
+		LoginID(c, id)
+		c.String(http.StatusOK, "ok")
+	})
+	r.Get("/me", func(c *jimohttp.Context) {
+		c.String(http.StatusOK, "ok")
+	}, jimohttp.WithMiddleware(Authenticate(func(id K) (string, bool) {
+		got, called = id, true
+		return "user", true
+	})))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/login", nil))
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	for _, c := range rec.Result().Cookies() {
+		req.AddCookie(c)
+	}
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	return got, called
+}
+
+func TestAuthenticateIntID(t *testing.T) {
+	got, called := loginAndLoad[int](t, 42)
+	if !called || got != 42 {
+		t.Fatalf("loader got %v (called %v), want 42", got, called)
+	}
+}
+
+func TestAuthenticateInt64ID(t *testing.T) {
+	got, called := loginAndLoad[int64](t, int64(7))
+	if !called || got != 7 {
+		t.Fatalf("loader got %v (called %v), want 7", got, called)
+	}
+}
+
+func TestAuthenticateStringID(t *testing.T) {
+	const uuid = "5f0c7c3e-8d2a-4d8e-9a53-1c2b3d4e5f60"
+	got, called := loginAndLoad[string](t, uuid)
+	if !called || got != uuid {
+		t.Fatalf("loader got %q (called %v), want %q", got, called, uuid)
+	}
+}
+
+func TestAuthenticateRawID(t *testing.T) {
+	got, called := loginAndLoad[any](t, "abc")
+	if !called || got != "abc" {
+		t.Fatalf("loader got %v (called %v), want abc", got, called)
+	}
+}
+
+func TestAuthenticateUnconvertibleIDIsGuest(t *testing.T) {
+	if _, called := loginAndLoad[int](t, "not-a-number"); called {
+		t.Fatal("loader called for an id that is not an int")
+	}
+}
+
+func TestLogoutClearsAuthState(t *testing.T) {
+	sm, err := jimohttp.NewSessionManager("test-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := jimohttp.NewRouter()
+	r.Use(jimohttp.Sessions(sm))
+	r.Get("/logout", func(c *jimohttp.Context) {
+		Login(c, 42)
+		c.Set(contextUserKey, "user")
+		sid := c.Session().ID()
+
+		Logout(c)
+
+		if _, ok := UserID(c); ok {
+			t.Error("UserID still reports a user after Logout")
+		}
+		if _, ok := c.Session().Values[sessionUserIDKey]; ok {
+			t.Error("session still holds the auth key after Logout")
+		}
+		if _, ok := User[string](c); ok {
+			t.Error("User still returns the cached user after Logout")
+		}
+		if c.Session().ID() == sid {
+			t.Error("session was not regenerated on Logout")
+		}
+		c.String(http.StatusOK, "ok")
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/logout", nil))
+}
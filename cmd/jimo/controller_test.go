@@ -0,0 +1,49 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestGeneratedControllersCompile builds every controller template against this
+// checkout of the framework.
+func TestGeneratedControllersCompile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a temporary module")
+	}
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not found")
+	}
+	root, err := filepath.Abs("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	gomod := "module example.com/app\n\ngo 1.22\n\nrequire github.com/jimo-go/framework v0.0.0\n\nreplace github.com/jimo-go/framework => " + root + "\n"
+	files := map[string]string{
+		"go.mod":                          gomod,
+		"controllers/post_controller.go":  basicControllerTmpl("Post"),
+		"controllers/api_controller.go":   apiControllerTmpl("Api"),
+		"controllers/photo_controller.go": resourceControllerTmpl("Photo"),
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cmd := exec.Command(gobin, "build", "./...")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("generated controllers do not compile: %v\n%s", err, out)
+	}
+}
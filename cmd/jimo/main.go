@@ -1,14 +1,25 @@
 package main
 
 import (
+	"bytes"
 	"errors"
 	"flag"
 	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
 	"io/fs"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/jimo-go/framework/core"
+	jimohttp "github.com/jimo-go/framework/http"
 )
 
 func main() {
@@ -33,16 +44,51 @@ func main() {
 			fmt.Fprintln(os.Stderr, "error:", err)
 			os.Exit(1)
 		}
+	case "down":
+		if err := runDown(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+	case "up":
+		if err := runUp(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
 	case "make:model":
 		if err := runMakeModel(os.Args[2:]); err != nil {
 			fmt.Fprintln(os.Stderr, "error:", err)
 			os.Exit(1)
 		}
+	case "make:migration":
+		if err := runMakeMigration(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
 	case "make:controller":
 		if err := runMakeController(os.Args[2:]); err != nil {
 			fmt.Fprintln(os.Stderr, "error:", err)
 			os.Exit(1)
 		}
+	case "make:auth":
+		if err := runMakeAuth(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+	case "route:list":
+		if err := runRouteList(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+	case "hash":
+		if err := runHash(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+	case "check":
+		if err := runCheck(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
 	default:
 		usage()
 		os.Exit(2)
@@ -51,11 +97,18 @@ func main() {
 
 func usage() {
 	fmt.Fprintln(os.Stderr, "Usage:")
-	fmt.Fprintln(os.Stderr, "  jimo new <project-name> [--module <module-path>] [--repo <git-url>] [--branch <branch>]")
+	fmt.Fprintln(os.Stderr, "  jimo new <project-name> [--module <module-path>] [--repo <git-url>] [--branch <branch>] [--from <dir>] [--tidy=false]")
 	fmt.Fprintln(os.Stderr, "  jimo serve [--port <port>] [--cmd <path>] ")
 	fmt.Fprintln(os.Stderr, "  jimo dev [--port <port>] [--cmd <path>]")
-	fmt.Fprintln(os.Stderr, "  jimo make:model <Name>")
+	fmt.Fprintln(os.Stderr, "  jimo down [--message <text>] [--retry <seconds>] [--secret <token>] [--allow <paths>]")
+	fmt.Fprintln(os.Stderr, "  jimo up")
+	fmt.Fprintln(os.Stderr, "  jimo make:model <Name> [field:type ...] [--migration]")
+	fmt.Fprintln(os.Stderr, "  jimo make:migration <name>")
 	fmt.Fprintln(os.Stderr, "  jimo make:controller <Name> [--api] [--resource]")
+	fmt.Fprintln(os.Stderr, "  jimo make:auth")
+	fmt.Fprintln(os.Stderr, "  jimo route:list [--cmd <path>] [--json]")
+	fmt.Fprintln(os.Stderr, "  jimo hash [password]            (reads the password from stdin when omitted)")
+	fmt.Fprintln(os.Stderr, "  jimo check [password] <hash>    (reads the password from stdin when omitted)")
 }
 
 func runNew(args []string) error {
@@ -65,8 +118,10 @@ func runNew(args []string) error {
 	module := fs.String("module", "", "Go module path for the new project (default: project name)")
 	repo := fs.String("repo", "https://github.com/jimo-go/jimo.git", "Skeleton repository URL")
 	branch := fs.String("branch", "main", "Skeleton repository branch")
+	tidy := fs.Bool("tidy", true, "Run go mod tidy in the new project")
+	from := fs.String("from", "", "Copy the skeleton from a local directory instead of cloning")
 
-	projectName, flagArgs, err := splitProjectArgs(args)
+	projectName, flagArgs, err := splitProjectArgs(args, "tidy")
 	if err != nil {
 		return err
 	}
@@ -85,7 +140,11 @@ func runNew(args []string) error {
 		return err
 	}
 
-	if err := runCmd("git", "clone", "--depth", "1", "--branch", *branch, *repo, projectDir); err != nil {
+	if src := strings.TrimSpace(*from); src != "" {
+		if err := copyDir(src, projectDir); err != nil {
+			return err
+		}
+	} else if err := runCmd("git", "clone", "--depth", "1", "--branch", *branch, *repo, projectDir); err != nil {
 		return err
 	}
 
@@ -97,13 +156,27 @@ func runNew(args []string) error {
 	if mod == "" {
 		mod = projectName
 	}
+	skeletonMod, err := readModulePath(projectDir)
+	if err != nil {
+		skeletonMod = "github.com/jimo-go/jimo"
+	}
 	if err := rewriteGoMod(projectDir, mod); err != nil {
 		return err
 	}
-	if err := rewriteImports(projectDir, "github.com/jimo-go/jimo", mod); err != nil {
+	if err := rewriteImports(projectDir, skeletonMod, mod); err != nil {
 		return err
 	}
 
+	// Failures below leave a usable project, so they only warn.
+	if *tidy {
+		if err := runCmdIn(projectDir, "go", "mod", "tidy"); err != nil {
+			fmt.Fprintln(os.Stderr, "warning: go mod tidy failed:", err)
+		}
+	}
+	if err := runCmdIn(projectDir, "git", "init", "--quiet"); err != nil {
+		fmt.Fprintln(os.Stderr, "warning: git init failed:", err)
+	}
+
 	fmt.Printf("Created %s\n", projectDir)
 	fmt.Printf("Next:\n")
 	fmt.Printf("  cd %s\n", projectDir)
@@ -111,18 +184,29 @@ func runNew(args []string) error {
 	return nil
 }
 
-func splitProjectArgs(args []string) (projectName string, flagArgs []string, err error) {
+func splitProjectArgs(args []string, boolFlags ...string) (projectName string, flagArgs []string, err error) {
 	// Allow flags anywhere:
 	// - jimo new myapp --module x
 	// - jimo new --module x myapp
+	// Boolean flags never consume the following argument.
 	pos := make([]string, 0, 1)
 	flags := make([]string, 0, len(args))
 
+	isBool := func(flagArg string) bool {
+		name := strings.TrimLeft(flagArg, "-")
+		for _, b := range boolFlags {
+			if name == b {
+				return true
+			}
+		}
+		return false
+	}
+
 	for i := 0; i < len(args); i++ {
 		a := args[i]
 		if strings.HasPrefix(a, "-") {
 			flags = append(flags, a)
-			if !strings.Contains(a, "=") && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+			if !strings.Contains(a, "=") && !isBool(a) && i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
 				flags = append(flags, args[i+1])
 				i++
 			}
@@ -190,6 +274,93 @@ func runDev(args []string) error {
 	return cmd.Run()
 }
 
+func runDown(args []string) error {
+	fs := flag.NewFlagSet("down", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	message := fs.String("message", "", "Message returned with the 503 response")
+	retry := fs.Int("retry", 0, "Retry-After header value in seconds")
+	secret := fs.String("secret", "", "Token that bypasses maintenance mode (visit /<token> or send X-Maintenance-Secret)")
+	allow := fs.String("allow", "", "Comma-separated paths that stay reachable (e.g. /health)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	mode := jimohttp.MaintenanceMode{
+		Message:    strings.TrimSpace(*message),
+		RetryAfter: *retry,
+		Secret:     strings.TrimSpace(*secret),
+	}
+	for _, p := range strings.Split(*allow, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			mode.Allow = append(mode.Allow, p)
+		}
+	}
+
+	if err := core.Down(core.MaintenanceFile, mode); err != nil {
+		return err
+	}
+	fmt.Println("Application is now in maintenance mode.")
+	if mode.Secret != "" {
+		fmt.Printf("Visit /%s to bypass it in your browser.\n", mode.Secret)
+	}
+	return nil
+}
+
+func runUp(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("too many arguments: %v", args)
+	}
+	if err := core.Up(core.MaintenanceFile); err != nil {
+		return err
+	}
+	fmt.Println("Application is now live.")
+	return nil
+}
+
+// copyDir copies the directory tree at src into dst, skipping .git directories.
+func copyDir(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("skeleton is not a directory: %s", src)
+	}
+
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return fs.SkipDir
+			}
+			return os.MkdirAll(target, 0o755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(target, b, fi.Mode().Perm())
+	})
+}
+
 func rewriteGoMod(projectDir, modulePath string) error {
 	path := filepath.Join(projectDir, "go.mod")
 	b, err := os.ReadFile(path)
@@ -218,9 +389,13 @@ func rewriteGoMod(projectDir, modulePath string) error {
 	return os.WriteFile(path, []byte(content), 0o644)
 }
 
+// rewriteImports rewrites import paths under fromModule to toModule in every .go file.
+//
+// Only import specs are touched; string literals, comments and identifiers that happen
+// to contain the module path are left alone.
 func rewriteImports(projectDir, fromModule, toModule string) error {
-	from := strings.TrimSuffix(strings.TrimSpace(fromModule), "/") + "/"
-	to := strings.TrimSuffix(strings.TrimSpace(toModule), "/") + "/"
+	from := strings.TrimSuffix(strings.TrimSpace(fromModule), "/")
+	to := strings.TrimSuffix(strings.TrimSpace(toModule), "/")
 	if from == to {
 		return nil
 	}
@@ -230,7 +405,7 @@ func rewriteImports(projectDir, fromModule, toModule string) error {
 			return walkErr
 		}
 		if d.IsDir() {
-			if d.Name() == ".git" {
+			if d.Name() == ".git" || d.Name() == "vendor" {
 				return fs.SkipDir
 			}
 			return nil
@@ -238,17 +413,43 @@ func rewriteImports(projectDir, fromModule, toModule string) error {
 		if !strings.HasSuffix(path, ".go") {
 			return nil
 		}
-		b, err := os.ReadFile(path)
+		return rewriteFileImports(path, from, to)
+	})
+}
+
+func rewriteFileImports(path, from, to string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	changed := false
+	for _, imp := range f.Imports {
+		p, err := strconv.Unquote(imp.Path.Value)
 		if err != nil {
-			return err
+			continue
 		}
-		in := string(b)
-		out := strings.ReplaceAll(in, from, to)
-		if out == in {
-			return nil
+		if p == from || strings.HasPrefix(p, from+"/") {
+			imp.Path.Value = strconv.Quote(to + strings.TrimPrefix(p, from))
+			changed = true
 		}
-		return os.WriteFile(path, []byte(out), 0o644)
-	})
+	}
+	if !changed {
+		return nil
+	}
+
+	ast.SortImports(fset, f)
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, f); err != nil {
+		return fmt.Errorf("format %s: %w", path, err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
 }
 
 func ensureAirToml(cmdPath string) error {
@@ -278,14 +479,53 @@ func ensureAirToml(cmdPath string) error {
 	return os.WriteFile(path, []byte(content), 0o644)
 }
 
+type modelField struct {
+	Name   string // Go field name, e.g. FirstName
+	Column string // column / JSON name, e.g. first_name
+	Type   string // Go type, e.g. string
+	Kind   string // field type as given on the command line, e.g. text
+}
+
+// modelFieldTypes maps the field types accepted by make:model to Go types.
+var modelFieldTypes = map[string]string{
+	"string":   "string",
+	"text":     "string",
+	"int":      "int",
+	"int64":    "int64",
+	"uint":     "uint",
+	"float":    "float64",
+	"float64":  "float64",
+	"bool":     "bool",
+	"time":     "time.Time",
+	"datetime": "time.Time",
+}
+
 func runMakeModel(args []string) error {
-	if len(args) < 1 {
+	var migration bool
+	pos := make([]string, 0, len(args))
+	for _, a := range args {
+		switch {
+		case a == "--migration" || a == "-m":
+			migration = true
+		case strings.HasPrefix(a, "-"):
+			return fmt.Errorf("unknown flag: %s", a)
+		default:
+			pos = append(pos, a)
+		}
+	}
+
+	if len(pos) < 1 {
 		return errors.New("missing model name")
 	}
-	name := args[0]
+	name := pos[0]
 	if name == "" {
 		return errors.New("model name cannot be empty")
 	}
+	fields, err := parseModelFields(pos[1:])
+	if err != nil {
+		return err
+	}
+
 	dir := "app/models"
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return err
@@ -294,24 +534,172 @@ func runMakeModel(args []string) error {
 	if _, err := os.Stat(file); err == nil {
 		return fmt.Errorf("model already exists: %s", file)
 	}
+
+	imports := "\t\"github.com/jimo-go/framework/database\"\n"
+	var body strings.Builder
+	for _, f := range fields {
+		if f.Type == "time.Time" && !strings.Contains(imports, `"time"`) {
+			imports = "\t\"time\"\n\n" + imports
+		}
+		fmt.Fprintf(&body, "\t%s %s `json:\"%s\" db:\"%s\"`\n", f.Name, f.Type, f.Column, f.Column)
+	}
+	if len(fields) == 0 {
+		body.WriteString("\t// Add fields here\n")
+	}
+
 	content := fmt.Sprintf(`package models
 
 import (
-	"github.com/jimo-go/framework/database"
-)
+%s)
 
 type %s struct {
 	ID int `+"`json:\"id\"`"+`
-	// Add fields here
-}
+%s}
 
 func (%s) TableName() string { return "%ss" }
 
 func %ss() *database.Record[%s] {
 	return database.Model[%s]()
 }
-`, name, name, strings.ToLower(name), strings.ToLower(name), name, name)
-	return os.WriteFile(file, []byte(content), 0o644)
+`, imports, name, body.String(), name, strings.ToLower(name), strings.ToLower(name), name, name)
+
+	formatted, err := format.Source([]byte(content))
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(file, formatted, 0o644); err != nil {
+		return err
+	}
+
+	if migration {
+		if len(fields) == 0 {
+			fmt.Println("Note: no fields given, migration skipped (use jimo make:migration)")
+			return nil
+		}
+		table := strings.ToLower(name) + "s"
+		_, err := writeMigration("create_"+table+"_table", createTableSQL(table, fields), "DROP TABLE "+table+";\n")
+		return err
+	}
+	return nil
+}
+
+// parseModelFields parses "name:type" field definitions.
+func parseModelFields(args []string) ([]modelField, error) {
+	fields := make([]modelField, 0, len(args))
+	seen := make(map[string]bool, len(args))
+	for _, arg := range args {
+		column, typ, ok := strings.Cut(arg, ":")
+		column = strings.ToLower(strings.TrimSpace(column))
+		typ = strings.ToLower(strings.TrimSpace(typ))
+		if !ok || column == "" || typ == "" {
+			return nil, fmt.Errorf("invalid field %q (expected name:type)", arg)
+		}
+		goType, known := modelFieldTypes[typ]
+		if !known {
+			return nil, fmt.Errorf("unknown type %q for field %q (supported: %s)", typ, column, supportedFieldTypes())
+		}
+		if column == "id" {
+			return nil, errors.New("field \"id\" is generated automatically")
+		}
+		if seen[column] {
+			return nil, fmt.Errorf("duplicate field %q", column)
+		}
+		seen[column] = true
+
+		goName := camelCase(column)
+		if goName == "" || !token.IsIdentifier(goName) {
+			return nil, fmt.Errorf("invalid field name %q", column)
+		}
+		fields = append(fields, modelField{Name: goName, Column: column, Type: goType, Kind: typ})
+	}
+	return fields, nil
+}
+
+func supportedFieldTypes() string {
+	names := make([]string, 0, len(modelFieldTypes))
+	for name := range modelFieldTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// camelCase converts snake_case or kebab-case to an exported Go identifier.
+func camelCase(s string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == '_' || r == '-' }) {
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
+// sqlColumnTypes maps make:model field types to portable SQL column types.
+var sqlColumnTypes = map[string]string{
+	"string":   "VARCHAR(255)",
+	"text":     "TEXT",
+	"int":      "INTEGER",
+	"int64":    "BIGINT",
+	"uint":     "BIGINT",
+	"float":    "DOUBLE PRECISION",
+	"float64":  "DOUBLE PRECISION",
+	"bool":     "BOOLEAN",
+	"time":     "TIMESTAMP",
+	"datetime": "TIMESTAMP",
+}
+
+func createTableSQL(table string, fields []modelField) string {
+	var b strings.Builder
+	b.WriteString("-- Adjust the id column for your database (e.g. BIGSERIAL on PostgreSQL, AUTO_INCREMENT on MySQL).\n")
+	fmt.Fprintf(&b, "CREATE TABLE %s (\n", table)
+	b.WriteString("    id INTEGER PRIMARY KEY")
+	for _, f := range fields {
+		fmt.Fprintf(&b, ",\n    %s %s", f.Column, sqlColumnTypes[f.Kind])
+	}
+	b.WriteString("\n);\n")
+	return b.String()
+}
+
+func runMakeMigration(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: jimo make:migration <name>")
+	}
+	name := strings.ToLower(strings.TrimSpace(args[0]))
+	if name == "" {
+		return errors.New("migration name cannot be empty")
+	}
+	_, err := writeMigration(name, "", "")
+	return err
+}
+
+// writeMigration writes a timestamped up/down SQL migration pair to database/migrations
+// and returns the common file prefix.
+func writeMigration(name, up, down string) (string, error) {
+	dir := filepath.Join("database", "migrations")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	name = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' {
+			return r
+		}
+		return '_'
+	}, name)
+	prefix := filepath.Join(dir, time.Now().UTC().Format("20060102150405")+"_"+name)
+
+	for _, f := range []struct{ path, content string }{
+		{prefix + ".up.sql", up},
+		{prefix + ".down.sql", down},
+	} {
+		if _, err := os.Stat(f.path); err == nil {
+			return "", fmt.Errorf("migration already exists: %s", f.path)
+		}
+		if err := os.WriteFile(f.path, []byte(f.content), 0o644); err != nil {
+			return "", err
+		}
+		fmt.Printf("Created %s\n", f.path)
+	}
+	return prefix, nil
 }
 
 func runMakeController(args []string) error {
@@ -356,14 +744,15 @@ func basicControllerTmpl(name string) string {
 	return fmt.Sprintf(`package controllers
 
 import (
-	"github.com/jimo-go/framework"
+	"net/http"
+
 	jimohttp "github.com/jimo-go/framework/http"
 )
 
 type %sController struct{}
 
 func (c *%sController) Index(ctx *jimohttp.Context) {
-	ctx.String("Hello from %sController Index")
+	ctx.String(http.StatusOK, "Hello from %sController Index")
 }
 
 func (c *%sController) Show(ctx *jimohttp.Context) {
@@ -376,34 +765,33 @@ func apiControllerTmpl(name string) string {
 	return fmt.Sprintf(`package controllers
 
 import (
-	"github.com/jimo-go/framework"
 	jimohttp "github.com/jimo-go/framework/http"
 )
 
 type %sController struct{}
 
 func (c *%sController) Index(ctx *jimohttp.Context) {
-	ctx.JSON(jimohttp.Map{"message": "%s index"})
+	ctx.OK(jimohttp.Map{"message": "%s index"})
 }
 
 func (c *%sController) Store(ctx *jimohttp.Context) {
 	// TODO: validate input and create
-	ctx.JSON(jimohttp.Map{"message": "%s created"})
+	ctx.Created(jimohttp.Map{"message": "%s created"})
 }
 
 func (c *%sController) Show(ctx *jimohttp.Context) {
 	// TODO: fetch and show
-	ctx.JSON(jimohttp.Map{"message": "%s show"})
+	ctx.OK(jimohttp.Map{"message": "%s show"})
 }
 
 func (c *%sController) Update(ctx *jimohttp.Context) {
 	// TODO: validate input and update
-	ctx.JSON(jimohttp.Map{"message": "%s updated"})
+	ctx.OK(jimohttp.Map{"message": "%s updated"})
 }
 
 func (c *%sController) Destroy(ctx *jimohttp.Context) {
 	// TODO: delete
-	ctx.JSON(jimohttp.Map{"message": "%s deleted"})
+	ctx.OK(jimohttp.Map{"message": "%s deleted"})
 }
 `, name, name, name, name, name, name, name, name, name, name, name)
 }
@@ -413,7 +801,8 @@ func resourceControllerTmpl(name string) string {
 	return `package controllers
 
 import (
-	"github.com/jimo-go/framework"
+	"net/http"
+
 	jimohttp "github.com/jimo-go/framework/http"
 )
 
@@ -421,43 +810,48 @@ type ` + name + `Controller struct{}
 
 func (c *` + name + `Controller) Index(ctx *jimohttp.Context) {
 	// TODO: list ` + lower + `
-	ctx.String("List ` + name + `")
+	ctx.String(http.StatusOK, "List ` + name + `")
 }
 
 func (c *` + name + `Controller) Create(ctx *jimohttp.Context) {
 	// TODO: show create form
-	ctx.String("Create ` + name + ` form")
+	ctx.String(http.StatusOK, "Create ` + name + ` form")
 }
 
 func (c *` + name + `Controller) Store(ctx *jimohttp.Context) {
 	// TODO: handle create form submission
-	ctx.String("Store ` + name + `")
+	ctx.String(http.StatusOK, "Store ` + name + `")
 }
 
 func (c *` + name + `Controller) Show(ctx *jimohttp.Context) {
 	// TODO: show single ` + lower + `
-	ctx.String("Show ` + name + `")
+	ctx.String(http.StatusOK, "Show ` + name + `")
 }
 
 func (c *` + name + `Controller) Edit(ctx *jimohttp.Context) {
 	// TODO: show edit form
-	ctx.String("Edit ` + name + ` form")
+	ctx.String(http.StatusOK, "Edit ` + name + ` form")
 }
 
 func (c *` + name + `Controller) Update(ctx *jimohttp.Context) {
 	// TODO: handle edit form submission
-	ctx.String("Update ` + name + `")
+	ctx.String(http.StatusOK, "Update ` + name + `")
 }
 
 func (c *` + name + `Controller) Destroy(ctx *jimohttp.Context) {
 	// TODO: delete ` + lower + `
-	ctx.String("Destroy ` + name + `")
+	ctx.String(http.StatusOK, "Destroy ` + name + `")
 }
 `
 }
 
 func runCmd(name string, args ...string) error {
+	return runCmdIn("", name, args...)
+}
+
+func runCmdIn(dir, name string, args ...string) error {
 	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
@@ -0,0 +1,350 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type scaffoldFile struct {
+	path    string
+	content string
+	// optional files are skipped (instead of failing) when they already exist.
+	optional bool
+}
+
+func runMakeAuth(args []string) error {
+	if len(args) > 0 {
+		return fmt.Errorf("too many arguments: %v", args)
+	}
+
+	mod, err := readModulePath(".")
+	if err != nil {
+		return err
+	}
+
+	files := []scaffoldFile{
+		{path: "app/models/user.go", content: authUserModelTmpl(), optional: true},
+		{path: "app/http/requests/auth.go", content: authRequestsTmpl()},
+		{path: "app/http/controllers/session_controller.go", content: sessionControllerTmpl(mod)},
+		{path: "app/http/controllers/register_controller.go", content: registerControllerTmpl(mod)},
+		{path: "routes/auth.go", content: authRoutesTmpl(mod)},
+		{path: "views/auth/login.html", content: loginViewTmpl},
+		{path: "views/auth/register.html", content: registerViewTmpl},
+	}
+
+	for _, f := range files {
+		if f.optional {
+			continue
+		}
+		if _, err := os.Stat(f.path); err == nil {
+			return fmt.Errorf("file already exists: %s", f.path)
+		}
+	}
+
+	for _, f := range files {
+		if _, err := os.Stat(f.path); err == nil {
+			fmt.Printf("Skipped %s (already exists)\n", f.path)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(f.path, []byte(f.content), 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("Created %s\n", f.path)
+	}
+
+	fmt.Printf("Next:\n")
+	fmt.Printf("  enable sessions with app.Web()\n")
+	fmt.Printf("  register the routes with routes.Auth(app)\n")
+	return nil
+}
+
+// readModulePath returns the module path declared in dir/go.mod.
+func readModulePath(dir string) (string, error) {
+	b, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		if mod, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(mod), `"`), nil
+		}
+	}
+	return "", errors.New("go.mod has no module directive")
+}
+
+func authUserModelTmpl() string {
+	return `package models
+
+import (
+	"github.com/jimo-go/framework/database"
+)
+
+type User struct {
+	ID       int    ` + "`json:\"id\"`" + `
+	Name     string ` + "`json:\"name\"`" + `
+	Email    string ` + "`json:\"email\"`" + `
+	Password string ` + "`json:\"-\" db:\"password\"`" + `
+}
+
+func (User) TableName() string { return "users" }
+
+func Users() *database.Record[User] {
+	return database.Model[User]()
+}
+`
+}
+
+func authRequestsTmpl() string {
+	return `package requests
+
+import (
+	"github.com/jimo-go/framework/validation"
+)
+
+type LoginRequest struct {
+	Email    string ` + "`json:\"email\"`" + `
+	Password string ` + "`json:\"password\"`" + `
+}
+
+var LoginRules = validation.Rules{
+	"email":    "required|email",
+	"password": "required",
+}
+
+type RegisterRequest struct {
+	Name                 string ` + "`json:\"name\"`" + `
+	Email                string ` + "`json:\"email\"`" + `
+	Password             string ` + "`json:\"password\"`" + `
+	PasswordConfirmation string ` + "`json:\"password_confirmation\"`" + `
+}
+
+var RegisterRules = validation.Rules{
+	"name":                  "required|max:255",
+	"email":                 "required|email|max:255",
+	"password":              "required|min:8",
+	"password_confirmation": "required",
+}
+`
+}
+
+func sessionControllerTmpl(mod string) string {
+	return fmt.Sprintf(`package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jimo-go/framework/auth"
+	jimohttp "github.com/jimo-go/framework/http"
+
+	"%[1]s/app/http/requests"
+	"%[1]s/app/models"
+)
+
+type SessionController struct{}
+
+// Create shows the login form.
+func (c *SessionController) Create(ctx *jimohttp.Context) {
+	ctx.View("auth/login", map[string]any{"CSRF": ctx.CSRFToken()})
+}
+
+// Store logs the user in.
+func (c *SessionController) Store(ctx *jimohttp.Context) {
+	req := requests.LoginRequest{
+		Email:    strings.TrimSpace(ctx.Request.FormValue("email")),
+		Password: ctx.Request.FormValue("password"),
+	}
+	if errs, failed := ctx.Validate(&req, requests.LoginRules); failed {
+		ctx.View("auth/login", map[string]any{"CSRF": ctx.CSRFToken(), "Email": req.Email, "Errors": errs.Fields})
+		return
+	}
+
+	user, ok := findUserByEmail(req.Email)
+	if !ok || !auth.CheckPassword(req.Password, user.Password) {
+		errs := map[string]string{"email": "These credentials do not match our records."}
+		ctx.View("auth/login", map[string]any{"CSRF": ctx.CSRFToken(), "Email": req.Email, "Errors": errs})
+		return
+	}
+
+	auth.Login(ctx, user.ID)
+	ctx.Session().Regenerate()
+	http.Redirect(ctx.ResponseWriter, ctx.Request, "/", http.StatusSeeOther)
+}
+
+// Destroy logs the user out.
+func (c *SessionController) Destroy(ctx *jimohttp.Context) {
+	auth.Logout(ctx)
+	http.Redirect(ctx.ResponseWriter, ctx.Request, "/login", http.StatusSeeOther)
+}
+
+func findUserByEmail(email string) (models.User, bool) {
+	users, err := models.Users().All()
+	if err != nil {
+		return models.User{}, false
+	}
+	for _, u := range users {
+		if strings.EqualFold(u.Email, email) {
+			return u, true
+		}
+	}
+	return models.User{}, false
+}
+`, mod)
+}
+
+func registerControllerTmpl(mod string) string {
+	return fmt.Sprintf(`package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/jimo-go/framework/auth"
+	jimohttp "github.com/jimo-go/framework/http"
+
+	"%[1]s/app/http/requests"
+	"%[1]s/app/models"
+)
+
+type RegisterController struct{}
+
+// Create shows the registration form.
+func (c *RegisterController) Create(ctx *jimohttp.Context) {
+	ctx.View("auth/register", map[string]any{"CSRF": ctx.CSRFToken()})
+}
+
+// Store registers a new user and logs them in.
+func (c *RegisterController) Store(ctx *jimohttp.Context) {
+	req := requests.RegisterRequest{
+		Name:                 strings.TrimSpace(ctx.Request.FormValue("name")),
+		Email:                strings.TrimSpace(ctx.Request.FormValue("email")),
+		Password:             ctx.Request.FormValue("password"),
+		PasswordConfirmation: ctx.Request.FormValue("password_confirmation"),
+	}
+
+	errs, failed := ctx.Validate(&req, requests.RegisterRules)
+	fields := errs.Fields
+	if fields == nil {
+		fields = map[string]string{}
+	}
+	if req.Password != req.PasswordConfirmation {
+		fields["password_confirmation"] = "password confirmation does not match"
+		failed = true
+	}
+	if _, exists := findUserByEmail(req.Email); exists {
+		fields["email"] = "email has already been taken"
+		failed = true
+	}
+	if failed {
+		ctx.View("auth/register", map[string]any{"CSRF": ctx.CSRFToken(), "Name": req.Name, "Email": req.Email, "Errors": fields})
+		return
+	}
+
+	hash, err := auth.HashPassword(req.Password)
+	if err != nil {
+		panic(jimohttp.HTTPError{Status: http.StatusInternalServerError, Message: "Failed to hash password", Err: err})
+	}
+	user := models.User{Name: req.Name, Email: req.Email, Password: hash}
+	if err := models.Users().Create(&user); err != nil {
+		panic(jimohttp.HTTPError{Status: http.StatusInternalServerError, Message: "Failed to create user", Err: err})
+	}
+
+	auth.Login(ctx, user.ID)
+	ctx.Session().Regenerate()
+	http.Redirect(ctx.ResponseWriter, ctx.Request, "/", http.StatusSeeOther)
+}
+`, mod)
+}
+
+func authRoutesTmpl(mod string) string {
+	return fmt.Sprintf(`package routes
+
+import (
+	"github.com/jimo-go/framework"
+	"github.com/jimo-go/framework/auth"
+
+	"%[1]s/app/http/controllers"
+)
+
+// Auth registers the login, registration and logout routes.
+//
+// It requires sessions (app.Web()).
+func Auth(app *jimo.App) {
+	sessions := &controllers.SessionController{}
+	register := &controllers.RegisterController{}
+
+	app.Get("/login", sessions.Create, jimo.Named("login"))
+	app.Post("/login", sessions.Store)
+	app.Post("/logout", sessions.Destroy, jimo.Named("logout"), jimo.WithMiddleware(auth.RequireAuth()))
+
+	app.Get("/register", register.Create, jimo.Named("register"))
+	app.Post("/register", register.Store)
+}
+`, mod)
+}
+
+const loginViewTmpl = `<!doctype html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>Log in</title>
+</head>
+<body>
+	<h1>Log in</h1>
+	<form method="POST" action="/login">
+		<input type="hidden" name="_token" value="{{ .CSRF }}">
+
+		<label for="email">Email</label>
+		<input id="email" type="email" name="email" value="{{ .Email }}" required autofocus>
+		{{ with .Errors }}{{ with index . "email" }}<p class="error">{{ . }}</p>{{ end }}{{ end }}
+
+		<label for="password">Password</label>
+		<input id="password" type="password" name="password" required>
+		{{ with .Errors }}{{ with index . "password" }}<p class="error">{{ . }}</p>{{ end }}{{ end }}
+
+		<button type="submit">Log in</button>
+	</form>
+	<p><a href="/register">Create an account</a></p>
+</body>
+</html>
+`
+
+const registerViewTmpl = `<!doctype html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<title>Register</title>
+</head>
+<body>
+	<h1>Register</h1>
+	<form method="POST" action="/register">
+		<input type="hidden" name="_token" value="{{ .CSRF }}">
+
+		<label for="name">Name</label>
+		<input id="name" type="text" name="name" value="{{ .Name }}" required autofocus>
+		{{ with .Errors }}{{ with index . "name" }}<p class="error">{{ . }}</p>{{ end }}{{ end }}
+
+		<label for="email">Email</label>
+		<input id="email" type="email" name="email" value="{{ .Email }}" required>
+		{{ with .Errors }}{{ with index . "email" }}<p class="error">{{ . }}</p>{{ end }}{{ end }}
+
+		<label for="password">Password</label>
+		<input id="password" type="password" name="password" required>
+		{{ with .Errors }}{{ with index . "password" }}<p class="error">{{ . }}</p>{{ end }}{{ end }}
+
+		<label for="password_confirmation">Confirm password</label>
+		<input id="password_confirmation" type="password" name="password_confirmation" required>
+		{{ with .Errors }}{{ with index . "password_confirmation" }}<p class="error">{{ . }}</p>{{ end }}{{ end }}
+
+		<button type="submit">Register</button>
+	</form>
+	<p><a href="/login">Already registered?</a></p>
+</body>
+</html>
+`
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/jimo-go/framework/auth"
+)
+
+// runHash prints the auth.HashPassword encoding of a password, e.g. for seeding
+// an admin user.
+func runHash(args []string) error {
+	if len(args) > 1 {
+		return errors.New("usage: jimo hash [password]")
+	}
+	password, err := passwordArg(args)
+	if err != nil {
+		return err
+	}
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		return err
+	}
+	fmt.Println(hash)
+	return nil
+}
+
+// runCheck verifies a password against a hash, exiting non-zero on mismatch.
+func runCheck(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errors.New("usage: jimo check [password] <hash>")
+	}
+	hash := args[len(args)-1]
+	password, err := passwordArg(args[:len(args)-1])
+	if err != nil {
+		return err
+	}
+	if !auth.CheckPassword(password, hash) {
+		return errors.New("password does not match")
+	}
+	fmt.Println("Password matches.")
+	return nil
+}
+
+// passwordArg returns the password given on the command line or, to keep it out of
+// the shell history, reads it from stdin when omitted or "-".
+func passwordArg(args []string) (string, error) {
+	if len(args) == 1 && args[0] != "-" {
+		return args[0], nil
+	}
+	password, err := readPassword("Password: ")
+	if err != nil {
+		return "", err
+	}
+	if password == "" {
+		return "", errors.New("password is empty")
+	}
+	return password, nil
+}
+
+// readPassword reads one line from stdin. On a terminal the prompt is shown and
+// echo is turned off with stty while typing.
+func readPassword(prompt string) (string, error) {
+	tty := isTerminal(os.Stdin)
+	if tty {
+		fmt.Fprint(os.Stderr, prompt)
+		if err := stty("-echo"); err == nil {
+			defer func() {
+				_ = stty("echo")
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+func stty(arg string) error {
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jimo-go/framework/core"
+)
+
+// runRouteList builds and runs the app with core.RoutesEnv set, which makes
+// Jimo.RouteList print the routes instead of serving, and prints them as a table.
+func runRouteList(args []string) error {
+	fs := flag.NewFlagSet("route:list", flag.ContinueOnError)
+	fs.SetOutput(os.Stderr)
+
+	cmdPath := fs.String("cmd", "./cmd/server", "Path to the server package")
+	asJSON := fs.Bool("json", false, "Print the routes as JSON")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var out bytes.Buffer
+	cmd := exec.Command("go", "run", *cmdPath)
+	cmd.Stdout = &out
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), core.RoutesEnv+"=1")
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	var routes []core.RouteEntry
+	found := false
+	sc := bufio.NewScanner(&out)
+	sc.Buffer(make([]byte, 0, 64<<10), 16<<20)
+	for sc.Scan() {
+		line, ok := strings.CutPrefix(sc.Text(), core.RoutesMarker)
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal([]byte(line), &routes); err != nil {
+			return fmt.Errorf("invalid route list: %w", err)
+		}
+		found = true
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	if !found {
+		return errors.New("the app exited without listing its routes; its main must call app.RouteList() after registering them")
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(routes)
+	}
+	printRoutes(routes)
+	return nil
+}
+
+func printRoutes(routes []core.RouteEntry) {
+	if len(routes) == 0 {
+		fmt.Println("No routes registered.")
+		return
+	}
+	hosts := false
+	for _, r := range routes {
+		if r.Host != "" {
+			hosts = true
+			break
+		}
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if hosts {
+		fmt.Fprintln(tw, "METHOD\tHOST\tPATH\tNAME\tMIDDLEWARE")
+	} else {
+		fmt.Fprintln(tw, "METHOD\tPATH\tNAME\tMIDDLEWARE")
+	}
+	for _, r := range routes {
+		if hosts {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\n", r.Method, r.Host, r.Pattern, r.Name, r.Middleware)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", r.Method, r.Pattern, r.Name, r.Middleware)
+	}
+	_ = tw.Flush()
+	fmt.Printf("\n%d routes\n", len(routes))
+}
@@ -0,0 +1,142 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BindConfig populates a struct from environment variables.
+//
+// Fields are read from the variable named by their `env` tag; `default` supplies a
+// value when the variable is unset, and `env:"KEY,required"` fails when it is unset
+// and has no default. Untagged struct fields are walked recursively.
+//
+//	type MailConfig struct {
+//		Host    string        `env:"MAIL_HOST" default:"localhost"`
+//		Port    int           `env:"MAIL_PORT" default:"587"`
+//		TLS     bool          `env:"MAIL_TLS"`
+//		Timeout time.Duration `env:"MAIL_TIMEOUT" default:"10s"`
+//		Admins  []string      `env:"MAIL_ADMINS"` // comma-separated
+//	}
+//
+// Supported types are strings, bools, integers, floats, time.Duration and string
+// slices. Call it after LoadEnv so .env values are visible. All field errors are
+// reported together.
+func BindConfig(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("config: BindConfig expects a non-nil pointer to a struct")
+	}
+	return errors.Join(bindConfigStruct(rv.Elem())...)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func bindConfigStruct(sv reflect.Value) []error {
+	var errs []error
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		f := st.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fv := sv.Field(i)
+
+		tag, tagged := f.Tag.Lookup("env")
+		if !tagged {
+			if f.Type.Kind() == reflect.Struct {
+				errs = append(errs, bindConfigStruct(fv)...)
+			}
+			continue
+		}
+
+		key, opts, _ := strings.Cut(tag, ",")
+		key = strings.TrimSpace(key)
+		if key == "" || key == "-" {
+			continue
+		}
+
+		raw, ok := os.LookupEnv(key)
+		if !ok {
+			raw, ok = f.Tag.Lookup("default")
+		}
+		if !ok {
+			if hasOption(opts, "required") {
+				errs = append(errs, fmt.Errorf("config: %s is required (%s)", key, f.Name))
+			}
+			continue
+		}
+
+		if err := setConfigValue(fv, strings.TrimSpace(raw)); err != nil {
+			errs = append(errs, fmt.Errorf("config: %s (%s): %w", key, f.Name, err))
+		}
+	}
+	return errs
+}
+
+func hasOption(opts, name string) bool {
+	for _, o := range strings.Split(opts, ",") {
+		if strings.TrimSpace(o) == name {
+			return true
+		}
+	}
+	return false
+}
+
+func setConfigValue(fv reflect.Value, raw string) error {
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", raw)
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		fv.SetFloat(n)
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", fv.Type())
+		}
+		parts := splitList(raw)
+		out := reflect.MakeSlice(fv.Type(), len(parts), len(parts))
+		for i, p := range parts {
+			out.Index(i).SetString(p)
+		}
+		fv.Set(out)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+	return nil
+}
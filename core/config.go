@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+
+	jimohttp "github.com/jimo-go/framework/http"
 )
 
 // Config holds framework-level configuration.
@@ -18,6 +20,25 @@ type Config struct {
 	Env   string
 	Debug bool
 	Key   string
+
+	// Name (APP_NAME) and Version (APP_VERSION) describe the application, e.g. in
+	// its OpenAPI document.
+	Name    string
+	Version string
+
+	// OpenAPIPath (OPENAPI_PATH) is where New serves the OpenAPI document; set it
+	// to an empty value to not serve it.
+	OpenAPIPath string
+
+	// TrustedHosts is the Host header allowlist read from TRUSTED_HOSTS (comma-separated).
+	TrustedHosts []string
+
+	// UploadMaxMemory (UPLOAD_MAX_MEMORY), UploadMaxSize (UPLOAD_MAX_SIZE) and
+	// UploadMaxFileSize (UPLOAD_MAX_FILE_SIZE) are the multipart limits in bytes;
+	// values accept KB, MB and GB suffixes.
+	UploadMaxMemory   int64
+	UploadMaxSize     int64
+	UploadMaxFileSize int64
 }
 
 // NewConfig reads configuration from the current process environment.
@@ -36,6 +57,13 @@ func (c *Config) RefreshFromEnv() {
 	c.Env = getenvDefault("APP_ENV", "local")
 	c.Debug = parseBool(getenvDefault("APP_DEBUG", "true"))
 	c.Key = getenvDefault("APP_KEY", "")
+	c.Name = getenvDefault("APP_NAME", "JIMO API")
+	c.Version = getenvDefault("APP_VERSION", "1.0.0")
+	c.OpenAPIPath = strings.TrimSpace(getenvDefault("OPENAPI_PATH", "/openapi.json"))
+	c.TrustedHosts = splitList(getenvDefault("TRUSTED_HOSTS", ""))
+	c.UploadMaxMemory = parseSize(getenvDefault("UPLOAD_MAX_MEMORY", ""), jimohttp.DefaultMultipartMemory)
+	c.UploadMaxSize = parseSize(getenvDefault("UPLOAD_MAX_SIZE", ""), jimohttp.DefaultMaxUploadSize)
+	c.UploadMaxFileSize = parseSize(getenvDefault("UPLOAD_MAX_FILE_SIZE", ""), jimohttp.DefaultMaxFileSize)
 }
 
 // LoadEnv loads a .env file and applies variables to the process environment.
@@ -130,6 +158,40 @@ func parseBool(v string) bool {
 	return b
 }
 
+// parseSize parses a byte count such as "10MB", returning def when v is empty or invalid.
+func parseSize(v string, def int64) int64 {
+	v = strings.ToUpper(strings.TrimSpace(v))
+	if v == "" {
+		return def
+	}
+	mult := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		mult   int64
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(v, unit.suffix) {
+			v = strings.TrimSpace(strings.TrimSuffix(v, unit.suffix))
+			mult = unit.mult
+			break
+		}
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n < 0 {
+		return def
+	}
+	return n * mult
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, part := range strings.Split(v, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
 func unquoteEnv(v string) string {
 	v = strings.TrimSpace(v)
 	if v == "" {
@@ -2,7 +2,10 @@ package core
 
 import (
 	"fmt"
+	"io"
 	"reflect"
+	"sort"
+	"strings"
 	"sync"
 )
 
@@ -15,16 +18,65 @@ type Provider func(*Container) (any, error)
 //
 // It is intentionally small and opinionated: services are registered by their Go type.
 // This enables an ergonomic, compile-time-friendly dependency injection style using generics.
+//
+// Bindings are transient by default: every Resolve calls the provider again.
+// Scoped bindings (BindScoped) are built once per Scope and cached there.
 type Container struct {
 	mu        sync.RWMutex
 	providers map[reflect.Type]Provider
+	scoped    map[reflect.Type]bool
+	tags      map[string][]reflect.Type
+
+	// parent and instances are only set on scopes created with Scope.
+	parent    *Container
+	instances map[reflect.Type]any
+	order     []reflect.Type
 }
 
 // NewContainer creates a new, empty service container.
 func NewContainer() *Container {
 	return &Container{
 		providers: make(map[reflect.Type]Provider),
+		scoped:    make(map[reflect.Type]bool),
+		tags:      make(map[string][]reflect.Type),
+	}
+}
+
+// Scope returns a child container for a unit of work such as a single request.
+//
+// The scope sees every binding of its parent and may add its own. Scoped services
+// are cached in the scope, so they are shared within it but not across scopes.
+// Call Close when the unit of work ends.
+func (c *Container) Scope() *Container {
+	child := NewContainer()
+	child.parent = c
+	child.instances = make(map[reflect.Type]any)
+	return child
+}
+
+// Close releases the services cached by a scope, calling Close on those implementing
+// io.Closer in reverse creation order. It returns the first error encountered.
+//
+// Close is a no-op on a container that is not a scope.
+func (c *Container) Close() error {
+	c.mu.Lock()
+	order := c.order
+	instances := c.instances
+	c.order = nil
+	if c.instances != nil {
+		c.instances = make(map[reflect.Type]any)
 	}
+	c.mu.Unlock()
+
+	var first error
+	for i := len(order) - 1; i >= 0; i-- {
+		if closer, ok := instances[order[i]].(io.Closer); ok {
+			if err := closer.Close(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
 }
 
 func typeKey[T any]() reflect.Type {
@@ -54,21 +106,180 @@ func (c *Container) Bind(t reflect.Type, provider Provider) error {
 	return nil
 }
 
+// BindScoped registers a provider whose service is built at most once per Scope.
+//
+// Scoped services can only be resolved from a scope.
+func (c *Container) BindScoped(t reflect.Type, provider Provider) error {
+	if err := c.Bind(t, provider); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.scoped[t] = true
+	c.mu.Unlock()
+	return nil
+}
+
+// lookup finds the provider for t in c or its ancestors.
+func (c *Container) lookup(t reflect.Type) (Provider, bool, bool) {
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		provider, ok := cur.providers[t]
+		scoped := cur.scoped[t]
+		cur.mu.RUnlock()
+		if ok {
+			return provider, scoped, true
+		}
+	}
+	return nil, false, false
+}
+
+// Has reports whether a provider is bound for t in the container or, for a scope,
+// its ancestors.
+func (c *Container) Has(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	_, _, ok := c.lookup(t)
+	return ok
+}
+
+// Bindings returns the bound service types visible from the container, including
+// those of a scope's ancestors, sorted by name. It is meant for debugging and for
+// checking at boot that required services are registered.
+func (c *Container) Bindings() []reflect.Type {
+	seen := make(map[reflect.Type]bool)
+	var out []reflect.Type
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		for t := range cur.providers {
+			if !seen[t] {
+				seen[t] = true
+				out = append(out, t)
+			}
+		}
+		cur.mu.RUnlock()
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].String() < out[j].String() })
+	return out
+}
+
 // Resolve constructs and returns a service instance for the given type.
 func (c *Container) Resolve(t reflect.Type) (any, error) {
 	if t == nil {
 		return nil, fmt.Errorf("container: type is nil")
 	}
 
+	provider, scoped, ok := c.lookup(t)
+	if !ok {
+		if hint := c.bindingHint(t); hint != "" {
+			return nil, fmt.Errorf("container: no provider bound for %s (%s)", t.String(), hint)
+		}
+		return nil, fmt.Errorf("container: no provider bound for %s", t.String())
+	}
+	if !scoped {
+		return provider(c)
+	}
+
+	if c.instances == nil {
+		return nil, fmt.Errorf("container: %s is scoped; resolve it from a Scope", t.String())
+	}
 	c.mu.RLock()
-	provider, ok := c.providers[t]
+	v, cached := c.instances[t]
 	c.mu.RUnlock()
+	if cached {
+		return v, nil
+	}
 
-	if !ok {
-		return nil, fmt.Errorf("container: no provider bound for %s", t.String())
+	v, err := provider(c)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, cached := c.instances[t]; cached {
+		// Another goroutine in the same scope won the race.
+		return existing, nil
+	}
+	c.instances[t] = v
+	c.order = append(c.order, t)
+	return v, nil
+}
+
+// bindingHint points at a bound type that is related to t through an interface,
+// which usually means a concrete type was bound but an interface was resolved (or vice versa).
+func (c *Container) bindingHint(t reflect.Type) string {
+	var related []string
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		for bound := range cur.providers {
+			switch {
+			case t.Kind() == reflect.Interface && bound.Implements(t):
+				related = append(related, bound.String())
+			case bound.Kind() == reflect.Interface && t.Implements(bound):
+				related = append(related, bound.String())
+			}
+		}
+		cur.mu.RUnlock()
+	}
+	if len(related) == 0 {
+		return ""
+	}
+	sort.Strings(related)
+	if t.Kind() == reflect.Interface {
+		return fmt.Sprintf("bound implementations: %s; register one with BindAs", strings.Join(related, ", "))
+	}
+	return fmt.Sprintf("bound interfaces: %s; resolve the interface instead", strings.Join(related, ", "))
+}
+
+// Tag groups bound types under a tag so they can be resolved together with ResolveTagged.
+//
+// Types are resolved in the order they were tagged; tagging a type twice is a no-op.
+// The types do not need to be bound yet, but must be by the time the tag is resolved.
+func (c *Container) Tag(tag string, types ...reflect.Type) error {
+	if tag == "" {
+		return fmt.Errorf("container: tag is empty")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, t := range types {
+		if t == nil {
+			return fmt.Errorf("container: type is nil")
+		}
+		tagged := false
+		for _, existing := range c.tags[tag] {
+			if existing == t {
+				tagged = true
+				break
+			}
+		}
+		if !tagged {
+			c.tags[tag] = append(c.tags[tag], t)
+		}
+	}
+	return nil
+}
+
+// ResolveTag constructs every service tagged with tag, in tag order.
+func (c *Container) ResolveTag(tag string) ([]any, error) {
+	var types []reflect.Type
+	for cur := c; cur != nil; cur = cur.parent {
+		cur.mu.RLock()
+		types = append(types, cur.tags[tag]...)
+		cur.mu.RUnlock()
 	}
 
-	return provider(c)
+	out := make([]any, 0, len(types))
+	for _, t := range types {
+		v, err := c.Resolve(t)
+		if err != nil {
+			return nil, fmt.Errorf("container: resolving tag %q: %w", tag, err)
+		}
+		out = append(out, v)
+	}
+	return out, nil
 }
 
 // MustResolve is like Resolve but panics on error.
@@ -97,8 +308,57 @@ func Bind[T any](c *Container, provider func(*Container) (T, error)) error {
 	})
 }
 
+// BindScoped registers a provider for type T that is built at most once per Scope.
+func BindScoped[T any](c *Container, provider func(*Container) (T, error)) error {
+	if c == nil {
+		return fmt.Errorf("container: container is nil")
+	}
+	if provider == nil {
+		return fmt.Errorf("container: provider is nil")
+	}
+
+	return c.BindScoped(typeKey[T](), func(c *Container) (any, error) {
+		return provider(c)
+	})
+}
+
+// BindAs registers a provider returning the concrete type T under the interface type I.
+//
+// Resolve[I] then returns the T built by provider, which makes swapping implementations
+// a one-line change:
+//
+//	core.BindAs[Mailer](c, func(*core.Container) (*SMTPMailer, error) { ... })
+//
+// BindAs returns an error if I is not an interface or T does not implement it.
+func BindAs[I any, T any](c *Container, provider func(*Container) (T, error)) error {
+	if c == nil {
+		return fmt.Errorf("container: container is nil")
+	}
+	if provider == nil {
+		return fmt.Errorf("container: provider is nil")
+	}
+
+	iface, impl := typeKey[I](), typeKey[T]()
+	if iface.Kind() != reflect.Interface {
+		return fmt.Errorf("container: %s is not an interface", iface.String())
+	}
+	if !impl.Implements(iface) {
+		return fmt.Errorf("container: %s does not implement %s", impl.String(), iface.String())
+	}
+
+	return c.Bind(iface, func(c *Container) (any, error) {
+		v, err := provider(c)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+}
+
 // Resolve returns an instance of type T by calling the registered provider.
 //
+// T may be an interface type bound with Bind[I] (a provider returning I) or BindAs.
+//
 // This is a package-level helper because Go does not support generic methods.
 func Resolve[T any](c *Container) (T, error) {
 	if c == nil {
@@ -129,3 +389,41 @@ func MustResolve[T any](c *Container) T {
 	}
 	return v
 }
+
+// Has reports whether a provider is bound for T.
+func Has[T any](c *Container) bool {
+	if c == nil {
+		return false
+	}
+	return c.Has(typeKey[T]())
+}
+
+// TypeOf returns the reflect.Type used as the container key for T, e.g. for Container.Tag.
+func TypeOf[T any]() reflect.Type {
+	return typeKey[T]()
+}
+
+// ResolveTagged resolves every service tagged with tag as a T.
+//
+// It returns an error if any tagged service does not satisfy T. With no tagged
+// services it returns an empty slice.
+func ResolveTagged[T any](c *Container, tag string) ([]T, error) {
+	if c == nil {
+		return nil, fmt.Errorf("container: container is nil")
+	}
+
+	vals, err := c.ResolveTag(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]T, 0, len(vals))
+	for _, v := range vals {
+		service, ok := v.(T)
+		if !ok {
+			return nil, fmt.Errorf("container: tagged service %T does not satisfy %s", v, typeKey[T]().String())
+		}
+		out = append(out, service)
+	}
+	return out, nil
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fmt"
 	"net/http"
 	"os"
 	"time"
@@ -18,9 +19,17 @@ type Jimo struct {
 
 	// Server is optional. If nil, Listen will create a default http.Server.
 	Server *http.Server
+
+	wrappers []func(http.Handler) http.Handler
 }
 
 // New creates a new Jimo application instance with a default container and router.
+//
+// The router always honors maintenance mode (see DownForMaintenance; the sentinel
+// file is checked at most once a second) and gives every request its own
+// container scope (see RequestScope). The OpenAPI document is served at
+// Config.OpenAPIPath. APP_KEY is the key for Context.EncryptParam. It panics when
+// APP_KEY is set but malformed.
 func New() *Jimo {
 	_ = AutoLoadEnv(".")
 	cfg := NewConfig()
@@ -29,11 +38,24 @@ func New() *Jimo {
 			cfg.Key = k
 		}
 	}
-	return &Jimo{
+	router := jimohttp.NewRouter()
+	router.Use(jimohttp.Maintenance(MaintenanceFile))
+	router.SetMultipartLimits(cfg.UploadMaxMemory, cfg.UploadMaxSize)
+	router.SetMaxFileSize(cfg.UploadMaxFileSize)
+	if err := router.SetParamKey(cfg.Key); err != nil {
+		panic(fmt.Errorf("core: invalid APP_KEY: %w", err))
+	}
+	router.SetDebug(debugResponses(cfg))
+	j := &Jimo{
 		Container: NewContainer(),
-		Router:    jimohttp.NewRouter(),
+		Router:    router,
 		Config:    cfg,
 	}
+	router.Use(scopeRequests(func() *Container { return j.Container }))
+	if cfg.OpenAPIPath != "" {
+		j.ServeOpenAPI(cfg.OpenAPIPath)
+	}
+	return j
 }
 
 // LoadEnv loads a dotenv file into the process environment (non-overwriting) and refreshes app config.
@@ -43,9 +65,10 @@ func (j *Jimo) LoadEnv(path string) error {
 	}
 	if j.Config == nil {
 		j.Config = NewConfig()
-		return nil
+	} else {
+		j.Config.RefreshFromEnv()
 	}
-	j.Config.RefreshFromEnv()
+	j.Router.SetDebug(debugResponses(j.Config))
 	return nil
 }
 
@@ -56,9 +79,10 @@ func (j *Jimo) AutoLoadEnv(dir string) error {
 	}
 	if j.Config == nil {
 		j.Config = NewConfig()
-		return nil
+	} else {
+		j.Config.RefreshFromEnv()
 	}
-	j.Config.RefreshFromEnv()
+	j.Router.SetDebug(debugResponses(j.Config))
 	return nil
 }
 
@@ -78,28 +102,25 @@ func (j *Jimo) Debug() bool {
 	return j.Config.Debug
 }
 
+// debugResponses reports whether error responses may include debug details:
+// APP_DEBUG is on and APP_ENV is not production.
+func debugResponses(cfg *Config) bool {
+	return cfg.Debug && cfg.Env != "production"
+}
+
 // Root returns the process working directory.
 func (j *Jimo) Root() string {
 	wd, _ := os.Getwd()
 	return wd
 }
 
-// Web enables the default "web" middleware stack: sessions + CSRF.
+// Web enables the default "web" middleware stack globally (see WebStack).
 func (j *Jimo) Web() error {
-	if j.Config == nil {
-		j.Config = NewConfig()
-	}
-
-	sm, err := jimohttp.NewSessionManager(j.Config.Key)
+	mw, err := j.WebStack()
 	if err != nil {
 		return err
 	}
-
-	j.Use(
-		jimohttp.Sessions(sm),
-		jimohttp.CSRF(sm),
-	)
-
+	j.Use(mw...)
 	return nil
 }
 
@@ -110,6 +131,43 @@ func (j *Jimo) MustWeb() {
 	}
 }
 
+// OnError sets a hook for shipping failures to an error tracker such as Sentry.
+//
+// It receives a *PanicError (with stack) for handler panics that are not HTTPErrors
+// (and 5xx HTTPErrors with jimohttp.ReportServerErrors), panics in Context.Defer
+// functions, and panics recovered by SafeGo, for which ctx is nil.
+func (j *Jimo) OnError(fn func(err error, ctx *jimohttp.Context), opts ...jimohttp.ErrorReportOption) {
+	j.Router.OnError(fn, opts...)
+	if fn == nil {
+		SetPanicHandler(nil)
+		return
+	}
+	SetPanicHandler(func(err error) { fn(err, nil) })
+}
+
+// OpenAPI returns an OpenAPI 3 document for the registered routes (see
+// jimohttp.Router.OpenAPI), titled and versioned by Config.Name and Config.Version.
+func (j *Jimo) OpenAPI() map[string]any {
+	var info jimohttp.OpenAPIInfo
+	if j.Config != nil {
+		info = jimohttp.OpenAPIInfo{Title: j.Config.Name, Version: j.Config.Version}
+	}
+	return j.Router.OpenAPI(info)
+}
+
+// ServeOpenAPI serves the OpenAPI document at path (default /openapi.json). The
+// document is built per request, so it includes routes registered later.
+//
+// New already serves it at Config.OpenAPIPath; call this to serve it elsewhere too.
+func (j *Jimo) ServeOpenAPI(path string) {
+	if path == "" {
+		path = "/openapi.json"
+	}
+	j.Router.Get(path, func(ctx *jimohttp.Context) {
+		ctx.JSONRaw(http.StatusOK, j.OpenAPI())
+	})
+}
+
 // Get registers a GET route.
 func (j *Jimo) Get(path string, handler jimohttp.HandlerFunc, opts ...jimohttp.RouteOption) {
 	j.Router.Get(path, handler, opts...)
@@ -120,9 +178,49 @@ func (j *Jimo) Post(path string, handler jimohttp.HandlerFunc, opts ...jimohttp.
 	j.Router.Post(path, handler, opts...)
 }
 
+// Put registers a PUT route.
+func (j *Jimo) Put(path string, handler jimohttp.HandlerFunc, opts ...jimohttp.RouteOption) {
+	j.Router.Put(path, handler, opts...)
+}
+
+// Patch registers a PATCH route.
+func (j *Jimo) Patch(path string, handler jimohttp.HandlerFunc, opts ...jimohttp.RouteOption) {
+	j.Router.Patch(path, handler, opts...)
+}
+
+// Delete registers a DELETE route.
+func (j *Jimo) Delete(path string, handler jimohttp.HandlerFunc, opts ...jimohttp.RouteOption) {
+	j.Router.Delete(path, handler, opts...)
+}
+
+// Head registers a HEAD route.
+func (j *Jimo) Head(path string, handler jimohttp.HandlerFunc, opts ...jimohttp.RouteOption) {
+	j.Router.Head(path, handler, opts...)
+}
+
+// Options registers an OPTIONS route.
+func (j *Jimo) Options(path string, handler jimohttp.HandlerFunc, opts ...jimohttp.RouteOption) {
+	j.Router.Options(path, handler, opts...)
+}
+
+// Add registers routes from a table (see jimohttp.Router.Add).
+func (j *Jimo) Add(routes []jimohttp.Route) {
+	j.Router.Add(routes)
+}
+
 // Group registers a group of routes under a common prefix.
-func (j *Jimo) Group(prefix string, fn func(r *jimohttp.Router)) {
-	j.Router.Group(prefix, fn)
+func (j *Jimo) Group(prefix string, fn func(r *jimohttp.Router), opts ...jimohttp.GroupOption) {
+	j.Router.Group(prefix, fn, opts...)
+}
+
+// Domain scopes routes to a host pattern such as "{tenant}.example.com".
+func (j *Jimo) Domain(host string, fn func(r *jimohttp.Router)) {
+	j.Router.Domain(host, fn)
+}
+
+// Mount delegates every request under prefix to handler with the prefix stripped.
+func (j *Jimo) Mount(prefix string, handler http.Handler) {
+	j.Router.Mount(prefix, handler)
 }
 
 // Use registers middleware globally for the application.
@@ -130,6 +228,39 @@ func (j *Jimo) Use(mw ...jimohttp.Middleware) {
 	j.Router.Use(mw...)
 }
 
+// MiddlewareGroup registers a named middleware stack for groups created with
+// jimohttp.Using, e.g. app.MiddlewareGroup("api", app.ApiStack()...).
+func (j *Jimo) MiddlewareGroup(name string, mw ...jimohttp.Middleware) {
+	j.Router.MiddlewareGroup(name, mw...)
+}
+
+// NotFound sets the handler for unmatched requests (see jimohttp.Router.SetNotFound).
+func (j *Jimo) NotFound(handler jimohttp.HandlerFunc) {
+	j.Router.SetNotFound(handler)
+}
+
+// WrapHandler wraps the application's http.Handler in standard net/http
+// middleware, such as OpenTelemetry's otelhttp.NewMiddleware.
+//
+// Wrappers run outside the router, before routing and before any framework
+// Middleware; the first one added is the outermost. Listen serves the wrapped
+// handler (see Handler), including when Server.Handler is unset.
+func (j *Jimo) WrapHandler(wrap func(http.Handler) http.Handler) {
+	if wrap != nil {
+		j.wrappers = append(j.wrappers, wrap)
+	}
+}
+
+// Handler returns the router wrapped by the WrapHandler wrappers, for serving the
+// application with a custom server or in tests.
+func (j *Jimo) Handler() http.Handler {
+	var h http.Handler = j.Router
+	for i := len(j.wrappers) - 1; i >= 0; i-- {
+		h = j.wrappers[i](h)
+	}
+	return h
+}
+
 // URL returns a route path by its name.
 func (j *Jimo) URL(name string, params map[string]string) string {
 	return j.Router.URL(name, params)
@@ -146,7 +277,7 @@ func (j *Jimo) Listen(addr string) error {
 	if srv == nil {
 		srv = &http.Server{
 			Addr:              addr,
-			Handler:           j.Router,
+			Handler:           j.Handler(),
 			ReadHeaderTimeout: 5 * time.Second,
 		}
 	}
@@ -155,7 +286,7 @@ func (j *Jimo) Listen(addr string) error {
 		srv.Addr = addr
 	}
 	if srv.Handler == nil {
-		srv.Handler = j.Router
+		srv.Handler = j.Handler()
 	}
 
 	return srv.ListenAndServe()
@@ -0,0 +1,28 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+func TestJimoVerbs(t *testing.T) {
+	app := New()
+	echo := func(c *jimohttp.Context) { c.String(http.StatusOK, c.Request.Method) }
+	app.Get("/r", echo)
+	app.Post("/r", echo)
+	app.Put("/r", echo)
+	app.Patch("/r", echo)
+	app.Delete("/r", echo)
+	app.Options("/r", echo)
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions} {
+		rec := httptest.NewRecorder()
+		app.Handler().ServeHTTP(rec, httptest.NewRequest(method, "/r", nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != method {
+			t.Errorf("%s /r = %d %q", method, rec.Code, rec.Body.String())
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+// MaintenanceFile is the sentinel file whose presence puts the application into maintenance mode.
+//
+// It is relative to the process working directory (the project root).
+const MaintenanceFile = "storage/framework/down"
+
+// Down writes the maintenance sentinel file at path.
+func Down(path string, mode jimohttp.MaintenanceMode) error {
+	b, err := json.MarshalIndent(mode, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// Up removes the maintenance sentinel file at path.
+//
+// It returns nil if the application is not down.
+func Up(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// DownForMaintenance puts the application into maintenance mode.
+func (j *Jimo) DownForMaintenance(mode jimohttp.MaintenanceMode) error {
+	return Down(MaintenanceFile, mode)
+}
+
+// Up brings the application out of maintenance mode.
+func (j *Jimo) Up() error {
+	return Up(MaintenanceFile)
+}
+
+// IsDownForMaintenance reports whether the maintenance sentinel file exists.
+func (j *Jimo) IsDownForMaintenance() bool {
+	_, err := os.Stat(MaintenanceFile)
+	return err == nil
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+func TestOpenAPIServedByDefault(t *testing.T) {
+	t.Setenv("APP_NAME", "Shop")
+	t.Setenv("APP_VERSION", "2.1.0")
+	app := New()
+	app.Get("/products", func(c *jimohttp.Context) {})
+
+	rec := httptest.NewRecorder()
+	app.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d", rec.Code)
+	}
+	var doc struct {
+		Info  map[string]string `json:"info"`
+		Paths map[string]any    `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Info["title"] != "Shop" || doc.Info["version"] != "2.1.0" {
+		t.Errorf("info = %v", doc.Info)
+	}
+	if _, ok := doc.Paths["/products"]; !ok {
+		t.Errorf("paths = %v, want /products", doc.Paths)
+	}
+}
+
+func TestOpenAPIPathConfig(t *testing.T) {
+	t.Setenv("OPENAPI_PATH", "/docs/api.json")
+	app := New()
+	for path, want := range map[string]int{"/docs/api.json": http.StatusOK, "/openapi.json": http.StatusNotFound} {
+		rec := httptest.NewRecorder()
+		app.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("%s: got %d, want %d", path, rec.Code, want)
+		}
+	}
+
+	t.Setenv("OPENAPI_PATH", "")
+	app = New()
+	rec := httptest.NewRecorder()
+	app.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("disabled: got %d", rec.Code)
+	}
+}
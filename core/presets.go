@@ -0,0 +1,61 @@
+package core
+
+import (
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+// WebStack returns the "web" middleware stack: trusted hosts, sessions and CSRF.
+//
+// The trusted hosts check is a no-op unless TRUSTED_HOSTS is configured. Use it
+// with Router.Use inside a group to enable it for browser routes only:
+//
+//	web, err := app.WebStack()
+//	app.Group("/", func(r *jimohttp.Router) {
+//		r.Use(web...)
+//		// ...
+//	})
+func (j *Jimo) WebStack() ([]jimohttp.Middleware, error) {
+	if j.Config == nil {
+		j.Config = NewConfig()
+	}
+
+	sm, err := jimohttp.NewSessionManager(j.Config.Key)
+	if err != nil {
+		return nil, err
+	}
+	return []jimohttp.Middleware{
+		jimohttp.TrustedHosts(j.Config.TrustedHosts...),
+		jimohttp.Sessions(sm),
+		jimohttp.CSRF(sm),
+	}, nil
+}
+
+// ApiStack returns the "api" middleware stack: trusted hosts, request IDs and
+// JSON request bodies (unsafe requests with a non-JSON body fail with 415), with
+// no sessions or CSRF. extra is appended, e.g. CORS or RateLimit.
+//
+// Panics are recovered by the router for every route, so the stack does not add
+// a recovery middleware. Use it with Router.Use inside a group to enable it for
+// API routes only:
+//
+//	app.Group("/api", func(r *jimohttp.Router) {
+//		r.Use(app.ApiStack()...)
+//		// ...
+//	})
+func (j *Jimo) ApiStack(extra ...jimohttp.Middleware) []jimohttp.Middleware {
+	if j.Config == nil {
+		j.Config = NewConfig()
+	}
+
+	mw := []jimohttp.Middleware{
+		jimohttp.TrustedHosts(j.Config.TrustedHosts...),
+		jimohttp.RequestID(),
+		jimohttp.RequireJSON(jimohttp.AllowEmptyBody()),
+	}
+	return append(mw, extra...)
+}
+
+// Api enables the "api" middleware stack globally (see ApiStack).
+func (j *Jimo) Api(extra ...jimohttp.Middleware) {
+	j.Use(j.ApiStack(extra...)...)
+}
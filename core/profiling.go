@@ -0,0 +1,62 @@
+package core
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"strings"
+
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+// EnableProfiling mounts the net/http/pprof handlers under prefix.
+//
+// The prefix defaults to /debug/pprof. Every profiling endpoint runs behind guard
+// (typically an auth middleware); in production a guard is required.
+func (j *Jimo) EnableProfiling(prefix string, guard jimohttp.Middleware) error {
+	prefix = strings.TrimSpace(prefix)
+	if prefix == "" {
+		prefix = "/debug/pprof"
+	}
+	if guard == nil && j.Env() == "production" {
+		return fmt.Errorf("profiling: a guard middleware is required in production")
+	}
+
+	j.Router.Group(prefix, func(r *jimohttp.Router) {
+		if guard != nil {
+			r.Use(guard)
+		}
+		r.Mount("/", http.HandlerFunc(servePprof))
+	})
+	return nil
+}
+
+// servePprof dispatches a prefix-stripped request to the matching pprof handler.
+func servePprof(w http.ResponseWriter, r *http.Request) {
+	name := strings.Trim(r.URL.Path, "/")
+	switch name {
+	case "":
+		// The index page uses relative links, so it must be served with a trailing slash.
+		if orig := requestPath(r); !strings.HasSuffix(orig, "/") {
+			http.Redirect(w, r, orig+"/", http.StatusMovedPermanently)
+			return
+		}
+		pprof.Index(w, r)
+	case "cmdline":
+		pprof.Cmdline(w, r)
+	case "profile":
+		pprof.Profile(w, r)
+	case "symbol":
+		pprof.Symbol(w, r)
+	case "trace":
+		pprof.Trace(w, r)
+	default:
+		pprof.Handler(name).ServeHTTP(w, r)
+	}
+}
+
+// requestPath returns the original, unstripped request path.
+func requestPath(r *http.Request) string {
+	path, _, _ := strings.Cut(r.RequestURI, "?")
+	return path
+}
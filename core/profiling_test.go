@@ -0,0 +1,64 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+func opsOnly(next jimohttp.HandlerFunc) jimohttp.HandlerFunc {
+	return func(c *jimohttp.Context) {
+		if c.Request.Header.Get("X-Ops") != "1" {
+			panic(jimohttp.HTTPError{Status: http.StatusUnauthorized, Message: "Unauthorized"})
+		}
+		next(c)
+	}
+}
+
+func get(h http.Handler, path string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestEnableProfilingEndpoints(t *testing.T) {
+	app := New()
+	if err := app.EnableProfiling("", opsOnly); err != nil {
+		t.Fatal(err)
+	}
+	h := app.Handler()
+
+	if rec := get(h, "/debug/pprof/cmdline", "X-Ops", "1"); rec.Code != http.StatusOK {
+		t.Fatalf("cmdline = %d", rec.Code)
+	}
+	if rec := get(h, "/debug/pprof/heap?debug=1", "X-Ops", "1"); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "heap profile") {
+		t.Fatalf("heap = %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := get(h, "/debug/pprof/", "X-Ops", "1"); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "goroutine") {
+		t.Fatalf("index = %d", rec.Code)
+	}
+	if rec := get(h, "/debug/pprof", "X-Ops", "1"); rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "/debug/pprof/" {
+		t.Fatalf("index without slash = %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+	if rec := get(h, "/debug/pprof/cmdline"); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("unguarded cmdline = %d, want 401", rec.Code)
+	}
+}
+
+func TestEnableProfilingRequiresGuardInProduction(t *testing.T) {
+	app := New()
+	app.Config.Env = "production"
+	if err := app.EnableProfiling("/pprof", nil); err == nil {
+		t.Fatal("EnableProfiling without a guard succeeded in production")
+	}
+	if rec := get(app.Handler(), "/pprof/cmdline"); rec.Code != http.StatusNotFound {
+		t.Fatalf("profiling mounted anyway: %d", rec.Code)
+	}
+}
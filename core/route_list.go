@@ -0,0 +1,72 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// RoutesEnv is set by `jimo route:list` when it runs the app; RouteList checks
+// it to print the routes instead of starting the server.
+const RoutesEnv = "JIMO_PRINT_ROUTES"
+
+// RoutesMarker starts the line PrintRoutes writes, so the CLI can find it among
+// other output.
+const RoutesMarker = "JIMO_ROUTES "
+
+// RouteEntry is a registered route as printed by PrintRoutes.
+type RouteEntry struct {
+	Method     string `json:"method"`
+	Host       string `json:"host,omitempty"`
+	Pattern    string `json:"pattern"`
+	Name       string `json:"name,omitempty"`
+	Middleware int    `json:"middleware"`
+}
+
+// RouteEntries returns the registered routes (see jimohttp.Router.Routes).
+func (j *Jimo) RouteEntries() []RouteEntry {
+	routes := j.Router.Routes()
+	out := make([]RouteEntry, 0, len(routes))
+	for _, r := range routes {
+		out = append(out, RouteEntry{
+			Method:     r.Method,
+			Host:       r.Host,
+			Pattern:    r.Pattern,
+			Name:       r.Name,
+			Middleware: r.Middleware,
+		})
+	}
+	return out
+}
+
+// RouteList is the entry point for `jimo route:list`. When the process was started
+// by that command (RoutesEnv is set) it prints the registered routes to stdout
+// (see PrintRoutes) and returns true, and main should exit without serving:
+//
+//	if app.RouteList() {
+//		return
+//	}
+//	log.Fatal(app.Listen(":8080"))
+//
+// Otherwise it does nothing and returns false.
+func (j *Jimo) RouteList() bool {
+	if os.Getenv(RoutesEnv) == "" {
+		return false
+	}
+	if err := j.PrintRoutes(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "route:list:", err)
+	}
+	return true
+}
+
+// PrintRoutes writes the registered routes to w as a single line: RoutesMarker
+// followed by a JSON array of RouteEntry.
+func (j *Jimo) PrintRoutes(w io.Writer) error {
+	b, err := json.Marshal(j.RouteEntries())
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s%s\n", RoutesMarker, b)
+	return err
+}
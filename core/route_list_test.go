@@ -0,0 +1,68 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+func TestPrintRoutes(t *testing.T) {
+	app := New()
+	app.Get("/users/{id}", func(c *jimohttp.Context) {}, jimohttp.Named("users.show"))
+
+	var out bytes.Buffer
+	if err := app.PrintRoutes(&out); err != nil {
+		t.Fatal(err)
+	}
+	line, ok := strings.CutPrefix(strings.TrimSpace(out.String()), RoutesMarker)
+	if !ok {
+		t.Fatalf("output %q lacks marker", out.String())
+	}
+	var routes []RouteEntry
+	if err := json.Unmarshal([]byte(line), &routes); err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, r := range routes {
+		if r.Method == "GET" && r.Pattern == "/users/{id}" && r.Name == "users.show" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("routes = %+v, want GET /users/{id}", routes)
+	}
+}
+
+func TestRouteListOnlyWhenRequested(t *testing.T) {
+	app := New()
+	app.Get("/", func(c *jimohttp.Context) {})
+
+	t.Setenv(RoutesEnv, "")
+	if app.RouteList() {
+		t.Fatal("RouteList reported true without RoutesEnv")
+	}
+
+	t.Setenv(RoutesEnv, "1")
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	listed := app.RouteList()
+	os.Stdout = stdout
+	w.Close()
+	out, _ := io.ReadAll(r)
+
+	if !listed {
+		t.Fatal("RouteList reported false with RoutesEnv set")
+	}
+	if !strings.HasPrefix(string(out), RoutesMarker) {
+		t.Fatalf("stdout = %q, want route list", out)
+	}
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"log"
+	"runtime/debug"
+	"sync"
+
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+// PanicError is a recovered panic together with the stack of the goroutine that
+// panicked.
+type PanicError = jimohttp.PanicError
+
+var (
+	panicHandler   func(err error)
+	panicHandlerMu sync.RWMutex
+)
+
+// SetPanicHandler sets the function SafeGo reports recovered panics to, as
+// *PanicError values. A nil handler restores the default, which logs the panic and
+// its stack.
+func SetPanicHandler(fn func(err error)) {
+	panicHandlerMu.Lock()
+	defer panicHandlerMu.Unlock()
+	panicHandler = fn
+}
+
+// SafeGo runs fn in a new goroutine that recovers from panics.
+//
+// The router only recovers panics on the request goroutine; an unrecovered panic in
+// any other goroutine terminates the process. Start background work with SafeGo so a
+// failing task is reported (see SetPanicHandler) instead.
+func SafeGo(fn func()) {
+	if fn == nil {
+		return
+	}
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				reportPanic(&PanicError{Value: rec, Stack: debug.Stack()})
+			}
+		}()
+		fn()
+	}()
+}
+
+func reportPanic(err *PanicError) {
+	panicHandlerMu.RLock()
+	fn := panicHandler
+	panicHandlerMu.RUnlock()
+
+	if fn == nil {
+		log.Printf("core: goroutine %v\n%s", err, err.Stack)
+		return
+	}
+	// A failing handler must not bring the process down either.
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("core: panic handler panicked: %v (reporting %v)", rec, err)
+		}
+	}()
+	fn(err)
+}
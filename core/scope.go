@@ -0,0 +1,64 @@
+package core
+
+import (
+	"sync"
+
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+const requestScopeKey = "core.scope"
+
+// requestScope lazily creates a container scope for a single request. Handlers may
+// resolve from it on several goroutines, so creation is guarded by mu.
+type requestScope struct {
+	root  func() *Container
+	mu    sync.Mutex
+	scope *Container
+}
+
+func (s *requestScope) get() *Container {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.scope == nil {
+		root := s.root()
+		if root == nil {
+			return nil
+		}
+		s.scope = root.Scope()
+	}
+	return s.scope
+}
+
+func (s *requestScope) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.scope != nil {
+		_ = s.scope.Close()
+	}
+}
+
+// scopeRequests gives every request its own container scope (see RequestScope).
+//
+// The scope is created on first use and closed when the request ends.
+func scopeRequests(root func() *Container) jimohttp.Middleware {
+	return func(next jimohttp.HandlerFunc) jimohttp.HandlerFunc {
+		return func(ctx *jimohttp.Context) {
+			s := &requestScope{root: root}
+			ctx.Set(requestScopeKey, s)
+			defer s.close()
+			next(ctx)
+		}
+	}
+}
+
+// RequestScope returns the container scope of the current request.
+//
+// Scoped services resolved from it are shared for the rest of the request and
+// released when it ends. It returns nil outside of a Jimo application router.
+func RequestScope(ctx *jimohttp.Context) *Container {
+	s, ok := ctx.Get(requestScopeKey).(*requestScope)
+	if !ok {
+		return nil
+	}
+	return s.get()
+}
@@ -0,0 +1,34 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+func TestRequestScopeConcurrentFirstUse(t *testing.T) {
+	app := New()
+	var scopes [16]*Container
+	app.Get("/", func(c *jimohttp.Context) {
+		var wg sync.WaitGroup
+		for i := range scopes {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				scopes[i] = RequestScope(c)
+			}(i)
+		}
+		wg.Wait()
+		c.String(http.StatusOK, "ok")
+	})
+
+	app.Handler().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	for i, s := range scopes {
+		if s == nil || s != scopes[0] {
+			t.Fatalf("scope %d = %p, want the shared scope %p", i, s, scopes[0])
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package database
 
-import "sync"
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
 
 // Connection is the minimal persistence contract used by the Active Record layer.
 //
@@ -14,6 +18,33 @@ type Connection interface {
 	Delete(table string, id any) error
 }
 
+// Updater is implemented by connections that can update the rows matching
+// conditions. Record needs it for UpdateColumns, UpdateWhere and models with a
+// custom primary key, and for Saves of versioned records, which it performs with
+// the version as an extra condition.
+type Updater interface {
+	// UpdateWhere sets only the given columns on rows matching where and returns the
+	// number of affected rows.
+	UpdateWhere(table string, where []Condition, changes map[string]any) (int64, error)
+}
+
+// Deleter is implemented by connections that can delete the rows matching
+// conditions. Record needs it to delete models with a custom primary key.
+type Deleter interface {
+	// DeleteWhere removes rows matching where and returns how many were removed.
+	DeleteWhere(table string, where []Condition) (int64, error)
+}
+
+// KeyInserter is implemented by connections that can insert into tables whose
+// primary key column is not "id", generating the key when the row has none.
+type KeyInserter interface {
+	// InsertKey inserts row and returns its pk value.
+	InsertKey(table, pk string, row map[string]any) (id any, err error)
+}
+
+// ErrStaleData is returned by Save when a versioned record was modified concurrently.
+var ErrStaleData = errors.New("record: stale data")
+
 var (
 	defaultConn Connection
 	defaultMu   sync.RWMutex
@@ -32,3 +63,33 @@ func Default() Connection {
 	defer defaultMu.RUnlock()
 	return defaultConn
 }
+
+// Querier is implemented by connections that can run raw queries, such as SQLConnection.
+type Querier interface {
+	Query(query string, args ...any) ([]map[string]any, error)
+}
+
+// Select runs a raw query and maps each result row into T.
+//
+// Columns are matched to fields by `db` tag, then `json` tag, then the lowercased
+// field name, so aliases in the query (SELECT count(*) AS total) map onto tagged fields.
+func Select[T any](conn Connection, query string, args ...any) ([]T, error) {
+	q, ok := conn.(Querier)
+	if !ok {
+		return nil, fmt.Errorf("database: connection %T does not support raw queries", conn)
+	}
+
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, 0, len(rows))
+	for _, row := range rows {
+		v, err := mapToStruct[T](row)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+	}
+	return out, nil
+}
@@ -1,8 +1,11 @@
 package database
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -31,6 +34,52 @@ func structToMap(v any) (map[string]any, error) {
 	return out, nil
 }
 
+// PatchColumns returns the column -> value changes for the fields of struct v whose
+// JSON names are in present, as reported by Context.BindPatch. The result suits
+// Record.UpdateColumns. Columns follow the usual mapping (`db` tag, `json` tag,
+// lowercased name); JSON names match case-insensitively, like encoding/json.
+func PatchColumns(v any, present map[string]bool) (map[string]any, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("record: expected struct")
+	}
+
+	folded := make(map[string]bool, len(present))
+	for k, ok := range present {
+		if ok {
+			folded[strings.ToLower(k)] = true
+		}
+	}
+
+	rt := rv.Type()
+	out := make(map[string]any)
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		jsonName, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if jsonName == "-" {
+			continue
+		}
+		if jsonName == "" {
+			jsonName = f.Name
+		}
+		if !folded[strings.ToLower(jsonName)] {
+			continue
+		}
+		column, ok := fieldName(f)
+		if !ok {
+			continue
+		}
+		out[column] = rv.Field(i).Interface()
+	}
+	return out, nil
+}
+
 func mapToStruct[T any](row map[string]any) (T, error) {
 	var out T
 	rv := reflect.ValueOf(&out).Elem()
@@ -86,7 +135,7 @@ func fieldName(f reflect.StructField) (string, bool) {
 	return strings.ToLower(f.Name), true
 }
 
-func getID(v any) (any, bool) {
+func getID(v any, pk string) (any, bool) {
 	rv := reflect.ValueOf(v)
 	if rv.Kind() == reflect.Pointer {
 		rv = rv.Elem()
@@ -94,10 +143,7 @@ func getID(v any) (any, bool) {
 	if rv.Kind() != reflect.Struct {
 		return nil, false
 	}
-	f := rv.FieldByName("ID")
-	if !f.IsValid() {
-		f = rv.FieldByName("Id")
-	}
+	f := pkField(rv, pk)
 	if !f.IsValid() {
 		return nil, false
 	}
@@ -107,7 +153,7 @@ func getID(v any) (any, bool) {
 	return f.Interface(), true
 }
 
-func setID(ptr any, id any) {
+func setID(ptr any, pk string, id any) {
 	rv := reflect.ValueOf(ptr)
 	if rv.Kind() != reflect.Pointer || rv.IsNil() {
 		return
@@ -116,14 +162,121 @@ func setID(ptr any, id any) {
 	if rv.Kind() != reflect.Struct {
 		return
 	}
+	f := pkField(rv, pk)
+	if !f.IsValid() || !f.CanSet() {
+		return
+	}
+	setValue(f, id)
+}
+
+// pkField returns the field mapped to the pk column, falling back to a field named
+// ID or Id for the conventional "id" column.
+func pkField(rv reflect.Value, pk string) reflect.Value {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if name, ok := fieldName(f); ok && name == pk {
+			return rv.Field(i)
+		}
+	}
+	if pk != "id" {
+		return reflect.Value{}
+	}
 	f := rv.FieldByName("ID")
 	if !f.IsValid() {
 		f = rv.FieldByName("Id")
 	}
-	if !f.IsValid() || !f.CanSet() {
-		return
+	return f
+}
+
+// versionField locates the optimistic-locking field of a struct: a field tagged
+// `db:",version"` or, failing that, an integer field named Version.
+func versionField(v any) (column string, version int64, index int, ok bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return "", 0, 0, false
+	}
+
+	rt := rv.Type()
+	index = -1
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if hasTagOption(f.Tag.Get("db"), "version") {
+			index = i
+			break
+		}
+		if f.Name == "Version" && index < 0 {
+			index = i
+		}
+	}
+	if index < 0 {
+		return "", 0, 0, false
+	}
+
+	f := rv.Field(index)
+	switch f.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	default:
+		return "", 0, 0, false
+	}
+	column, named := fieldName(rt.Field(index))
+	if !named {
+		return "", 0, 0, false
+	}
+	return column, f.Int(), index, true
+}
+
+func hasTagOption(tag, option string) bool {
+	parts := strings.Split(tag, ",")
+	for _, p := range parts[1:] {
+		if strings.TrimSpace(p) == option {
+			return true
+		}
+	}
+	return false
+}
+
+func toInt64(v any) (int64, bool) {
+	switch x := v.(type) {
+	case int:
+		return int64(x), true
+	case int8:
+		return int64(x), true
+	case int16:
+		return int64(x), true
+	case int32:
+		return int64(x), true
+	case int64:
+		return x, true
+	case uint:
+		return int64(x), uint64(x) <= math.MaxInt64
+	case uint8:
+		return int64(x), true
+	case uint16:
+		return int64(x), true
+	case uint32:
+		return int64(x), true
+	case uint64:
+		return int64(x), x <= math.MaxInt64
+	case float32:
+		return int64(x), x >= math.MinInt64 && x < math.MaxInt64 && float32(int64(x)) == x
+	case float64:
+		return int64(x), x >= math.MinInt64 && x < math.MaxInt64 && float64(int64(x)) == x
+	case json.Number:
+		n, err := x.Int64()
+		return n, err == nil
+	default:
+		return 0, false
 	}
-	setValue(f, id)
 }
 
 func setValue(dst reflect.Value, v any) {
@@ -151,6 +304,22 @@ func setValue(dst reflect.Value, v any) {
 			dst.SetInt(x)
 		case float64:
 			dst.SetInt(int64(x))
+		case json.Number:
+			if n, err := x.Int64(); err == nil {
+				dst.SetInt(n)
+			}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if x, ok := v.(json.Number); ok {
+			if n, err := strconv.ParseUint(string(x), 10, 64); err == nil {
+				dst.SetUint(n)
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		if x, ok := v.(json.Number); ok {
+			if n, err := x.Float64(); err == nil {
+				dst.SetFloat(n)
+			}
 		}
 	case reflect.String:
 		switch x := v.(type) {
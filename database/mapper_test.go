@@ -0,0 +1,48 @@
+package database
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMapToStructJSONNumber(t *testing.T) {
+	type row struct {
+		ID    int64   `db:"id"`
+		Hits  uint64  `db:"hits"`
+		Score float64 `db:"score"`
+		Count int     `db:"count"`
+	}
+	got, err := mapToStruct[row](map[string]any{
+		"id":    json.Number("9007199254740993"),
+		"hits":  json.Number("18446744073709551615"),
+		"score": json.Number("1.5"),
+		"count": float64(3),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := row{ID: 9007199254740993, Hits: 18446744073709551615, Score: 1.5, Count: 3}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestToInt64(t *testing.T) {
+	for _, tc := range []struct {
+		in   any
+		want int64
+		ok   bool
+	}{
+		{int(7), 7, true},
+		{float64(7), 7, true},
+		{float64(7.5), 0, false},
+		{json.Number("9007199254740993"), 9007199254740993, true},
+		{json.Number("1.5"), 0, false},
+		{"7", 0, false},
+	} {
+		got, ok := toInt64(tc.in)
+		if ok != tc.ok || (ok && got != tc.want) {
+			t.Errorf("toInt64(%#v) = %d, %v; want %d, %v", tc.in, got, ok, tc.want, tc.ok)
+		}
+	}
+}
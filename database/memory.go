@@ -1,17 +1,26 @@
 package database
 
 import (
+	"encoding/json"
 	"fmt"
+	"reflect"
+	"strconv"
 	"sync"
 )
 
+// MemoryConnection is an in-process Connection, useful for tests and prototyping.
+//
+// Rows are kept in insertion order: All, Filter and First return them in the order
+// they were inserted, updates keep a row's position, and a deleted id that is
+// inserted again moves to the end.
 type MemoryConnection struct {
 	mu     sync.RWMutex
 	tables map[string]*memoryTable
 }
 
 type memoryTable struct {
-	auto  int
+	pk    string // primary key column; rows are keyed by its value
+	auto  int64
 	rows  map[any]map[string]any
 	order []any
 }
@@ -20,13 +29,97 @@ func NewMemoryConnection() *MemoryConnection {
 	return &MemoryConnection{tables: make(map[string]*memoryTable)}
 }
 
-func (m *MemoryConnection) table(name string) *memoryTable {
-	t := m.tables[name]
-	if t == nil {
-		t = &memoryTable{rows: make(map[any]map[string]any)}
-		m.tables[name] = t
+// Snapshot copies every table and returns a function that restores them, for
+// isolating tests that share a connection:
+//
+//	restore := conn.Snapshot()
+//	defer restore()
+//
+// The copy is deep (nested maps and slices in rows are copied too), so changes
+// made after the snapshot never leak into it. The restore function can be called
+// more than once.
+func (m *MemoryConnection) Snapshot() func() {
+	m.mu.RLock()
+	saved := copyTables(m.tables)
+	m.mu.RUnlock()
+
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.tables = copyTables(saved)
+	}
+}
+
+// Reset removes all tables and their rows; auto-increment ids start over.
+func (m *MemoryConnection) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tables = make(map[string]*memoryTable)
+}
+
+func copyTables(in map[string]*memoryTable) map[string]*memoryTable {
+	out := make(map[string]*memoryTable, len(in))
+	for name, t := range in {
+		cp := &memoryTable{
+			pk:    t.pk,
+			auto:  t.auto,
+			rows:  make(map[any]map[string]any, len(t.rows)),
+			order: append([]any(nil), t.order...),
+		}
+		for id, row := range t.rows {
+			cp.rows[id] = deepCopy(row).(map[string]any)
+		}
+		out[name] = cp
+	}
+	return out
+}
+
+// deepCopy copies maps, slices and arrays recursively; other values (including
+// pointers) are shared.
+func deepCopy(v any) any {
+	if v == nil {
+		return nil
+	}
+	return deepCopyValue(reflect.ValueOf(v)).Interface()
+}
+
+func deepCopyValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), deepCopyValue(iter.Value()))
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopyValue(v.Index(i)))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopyValue(v.Index(i)))
+		}
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(deepCopyValue(v.Elem()))
+		return out
+	default:
+		return v
 	}
-	return t
 }
 
 func (m *MemoryConnection) Find(table string, id any) (map[string]any, bool, error) {
@@ -37,7 +130,7 @@ func (m *MemoryConnection) Find(table string, id any) (map[string]any, bool, err
 	if t == nil {
 		return nil, false, nil
 	}
-	row := t.rows[id]
+	row := t.rows[memoryKey(id)]
 	if row == nil {
 		return nil, false, nil
 	}
@@ -80,29 +173,60 @@ func (m *MemoryConnection) All(table string) ([]map[string]any, error) {
 	return out, nil
 }
 
+// Filter returns the rows matching where, in insertion order; pk is not used.
+func (m *MemoryConnection) Filter(table, pk string, where []Condition) ([]map[string]any, error) {
+	if err := checkConditions(where); err != nil {
+		return nil, err
+	}
+	rows, err := m.All(table)
+	if err != nil {
+		return nil, err
+	}
+	return filterRows(rows, where), nil
+}
+
 func (m *MemoryConnection) Insert(table string, row map[string]any) (any, error) {
+	return m.InsertKey(table, "id", row)
+}
+
+// InsertKey inserts row keyed by its pk column, assigning the next auto-increment
+// value when the row has none. The first insert into a table fixes its key column.
+func (m *MemoryConnection) InsertKey(table, pk string, row map[string]any) (any, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	t := m.table(table)
+	t := m.tables[table]
+	if t == nil {
+		t = &memoryTable{pk: pk, rows: make(map[any]map[string]any)}
+		m.tables[table] = t
+	}
+	if t.pk != pk {
+		return nil, fmt.Errorf("memory db: table %s is keyed by %s, not %s", table, t.pk, pk)
+	}
 
-	id := row["id"]
-	if id == nil {
+	id := row[pk]
+	key := memoryKey(id)
+	if key == nil {
 		t.auto++
-		id = t.auto
-		row["id"] = id
+		key = t.auto
+		id = key
 	}
-
-	if _, exists := t.rows[id]; exists {
+	if _, exists := t.rows[key]; exists {
 		return nil, fmt.Errorf("memory db: duplicate id")
 	}
+	// Keep auto ids ahead of explicitly inserted numeric ones.
+	if n, ok := key.(int64); ok && n > t.auto {
+		t.auto = n
+	}
+	row[pk] = id
 
-	t.rows[id] = cloneRow(row)
-	t.order = append(t.order, id)
+	t.rows[key] = cloneRow(row)
+	t.order = append(t.order, key)
 	return id, nil
 }
 
 func (m *MemoryConnection) Update(table string, id any, row map[string]any) error {
+	id = memoryKey(id)
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -110,16 +234,46 @@ func (m *MemoryConnection) Update(table string, id any, row map[string]any) erro
 	if t == nil {
 		return fmt.Errorf("memory db: table not found")
 	}
-	if _, ok := t.rows[id]; !ok {
+	existing, ok := t.rows[id]
+	if !ok {
 		return fmt.Errorf("memory db: row not found")
 	}
 
-	row["id"] = id
+	row[t.pk] = existing[t.pk]
 	t.rows[id] = cloneRow(row)
 	return nil
 }
 
+func (m *MemoryConnection) UpdateWhere(table string, where []Condition, changes map[string]any) (int64, error) {
+	if err := checkConditions(where); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t := m.tables[table]
+	if t == nil || len(changes) == 0 {
+		return 0, nil
+	}
+	if _, ok := changes[t.pk]; ok {
+		return 0, fmt.Errorf("memory db: cannot change primary key %s", t.pk)
+	}
+	var affected int64
+	for _, id := range t.order {
+		row := t.rows[id]
+		if row == nil || !matchRow(row, where) {
+			continue
+		}
+		for col, v := range changes {
+			row[col] = v
+		}
+		affected++
+	}
+	return affected, nil
+}
+
 func (m *MemoryConnection) Delete(table string, id any) error {
+	id = memoryKey(id)
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -138,6 +292,64 @@ func (m *MemoryConnection) Delete(table string, id any) error {
 	return nil
 }
 
+func (m *MemoryConnection) DeleteWhere(table string, where []Condition) (int64, error) {
+	if err := checkConditions(where); err != nil {
+		return 0, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t := m.tables[table]
+	if t == nil {
+		return 0, nil
+	}
+	kept := t.order[:0]
+	var affected int64
+	for _, id := range t.order {
+		if row := t.rows[id]; row != nil && matchRow(row, where) {
+			delete(t.rows, id)
+			affected++
+			continue
+		}
+		kept = append(kept, id)
+	}
+	t.order = kept
+	return affected, nil
+}
+
+// memoryKey maps an id to the canonical map key used by MemoryConnection, so the
+// same id matches however it was typed:
+//
+//   - integers of any type that fit in an int64 become int64
+//   - whole floats and json.Numbers (as decoded from JSON) become int64 too;
+//     fractional json.Numbers become float64
+//   - strings holding a canonical decimal integer ("1", not "01" or "+1") become
+//     int64 as well, so ids taken from URLs match
+//   - anything else, such as UUID strings or 1.5, is used as is
+//
+// Rows keep the id value they were inserted with; only the lookup key is normalized.
+func memoryKey(id any) any {
+	switch x := id.(type) {
+	case string:
+		if n, err := strconv.ParseInt(x, 10, 64); err == nil && strconv.FormatInt(n, 10) == x {
+			return n
+		}
+		return x
+	case json.Number:
+		if n, err := x.Int64(); err == nil {
+			return n
+		}
+		// Other numbers ("3.0", "1e3", "1.5") are keyed like the float64 they decode to.
+		if f, err := x.Float64(); err == nil {
+			id = f
+		}
+	}
+	if n, ok := toInt64(id); ok {
+		return n
+	}
+	return id
+}
+
 func cloneRow(in map[string]any) map[string]any {
 	out := make(map[string]any, len(in))
 	for k, v := range in {
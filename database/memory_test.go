@@ -0,0 +1,84 @@
+package database
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestMemoryKey(t *testing.T) {
+	for _, tc := range []struct {
+		in   any
+		want any
+	}{
+		{int(7), int64(7)},
+		{int32(7), int64(7)},
+		{int64(7), int64(7)},
+		{uint8(7), int64(7)},
+		{float64(7), int64(7)},
+		{json.Number("7"), int64(7)},
+		{json.Number("7.0"), int64(7)},
+		{json.Number("1e3"), int64(1000)},
+		{json.Number("9007199254740993"), int64(9007199254740993)},
+		{"7", int64(7)},
+		{float64(7.5), float64(7.5)},
+		{json.Number("7.5"), float64(7.5)},
+		{"07", "07"},
+		{"+7", "+7"},
+		{"7.0", "7.0"},
+		{"9223372036854775808", "9223372036854775808"},
+		{uint64(math.MaxUint64), uint64(math.MaxUint64)},
+		{"3f2c-uuid", "3f2c-uuid"},
+	} {
+		if got := memoryKey(tc.in); got != tc.want {
+			t.Errorf("memoryKey(%#v) = %#v, want %#v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestMemoryConnectionFindsIDsOfAnyType(t *testing.T) {
+	conn := NewMemoryConnection()
+	id, err := conn.Insert("users", map[string]any{"id": 42, "name": "Ada"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 42 {
+		t.Fatalf("Insert returned %#v, want the id as inserted", id)
+	}
+
+	for _, key := range []any{42, int64(42), float64(42), json.Number("42"), "42"} {
+		row, ok, err := conn.Find("users", key)
+		if err != nil || !ok || row["name"] != "Ada" {
+			t.Errorf("Find(%#v) = %v, %v, %v", key, row, ok, err)
+		}
+	}
+	if _, ok, _ := conn.Find("users", "042"); ok {
+		t.Error(`Find("042") matched id 42`)
+	}
+
+	if err := conn.Update("users", "42", map[string]any{"name": "Grace"}); err != nil {
+		t.Fatal(err)
+	}
+	if row, _, _ := conn.Find("users", int32(42)); row["name"] != "Grace" || row["id"] != 42 {
+		t.Errorf("after Update: %v", row)
+	}
+	if err := conn.Delete("users", float64(42)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := conn.Find("users", 42); ok {
+		t.Error("row still present after Delete")
+	}
+}
+
+func TestMemoryConnectionStringKeys(t *testing.T) {
+	conn := NewMemoryConnection()
+	if _, err := conn.InsertKey("posts", "slug", map[string]any{"slug": "hello", "title": "Hi"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := conn.InsertKey("posts", "slug", map[string]any{"slug": "hello"}); err == nil {
+		t.Error("duplicate string key inserted")
+	}
+	if row, ok, _ := conn.Find("posts", "hello"); !ok || row["title"] != "Hi" {
+		t.Errorf("Find = %v, %v", row, ok)
+	}
+}
@@ -0,0 +1,347 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Operators understood by Condition.
+const (
+	OpEq      = "="
+	OpNotEq   = "!="
+	OpLt      = "<"
+	OpLte     = "<="
+	OpGt      = ">"
+	OpGte     = ">="
+	OpIn      = "in"
+	OpNull    = "null"
+	OpNotNull = "not null"
+)
+
+// Condition is a single filter in a query.
+//
+// Conditions are combined in order with AND, except those with Or set, which start
+// a new OR branch: a AND b OR c reads as (a AND b) OR c, as in SQL.
+type Condition struct {
+	Column string
+	Op     string
+	// Value is the operand; a []any for OpIn and unused for OpNull/OpNotNull.
+	Value any
+	Or    bool
+}
+
+// Filterer is implemented by connections that can evaluate conditions themselves.
+//
+// Filter returns the rows matching where, ordered by the pk column unless it is
+// empty or the connection keeps its own order. Connections without it fall back
+// to loading All rows and filtering in memory.
+type Filterer interface {
+	Filter(table, pk string, where []Condition) ([]map[string]any, error)
+}
+
+func checkConditions(where []Condition) error {
+	for _, c := range where {
+		if strings.TrimSpace(c.Column) == "" {
+			return fmt.Errorf("record: condition has no column")
+		}
+		switch c.Op {
+		case OpEq, OpNotEq, OpLt, OpLte, OpGt, OpGte, OpNull, OpNotNull:
+		case OpIn:
+			if _, ok := c.Value.([]any); !ok {
+				return fmt.Errorf("record: %q in condition needs a []any value", c.Column)
+			}
+		default:
+			return fmt.Errorf("record: unsupported operator %q", c.Op)
+		}
+	}
+	return nil
+}
+
+// andAll returns where with c added to every OR branch, so the result matches
+// the rows matching both where and c.
+func andAll(where []Condition, c Condition) []Condition {
+	out := make([]Condition, 0, len(where)+2)
+	for i, w := range where {
+		if w.Or && i > 0 {
+			out = append(out, c)
+		}
+		out = append(out, w)
+	}
+	return append(out, c)
+}
+
+// filterRows evaluates where against rows; it backs MemoryConnection.Filter and the
+// fallback for connections that are not Filterers.
+func filterRows(rows []map[string]any, where []Condition) []map[string]any {
+	if len(where) == 0 {
+		return rows
+	}
+	out := rows[:0:0]
+	for _, row := range rows {
+		if matchRow(row, where) {
+			out = append(out, row)
+		}
+	}
+	return out
+}
+
+func matchRow(row map[string]any, where []Condition) bool {
+	branch := true
+	for i, c := range where {
+		if c.Or && i > 0 {
+			if branch {
+				return true
+			}
+			branch = true
+		}
+		if branch && !matchCondition(row, c) {
+			branch = false
+		}
+	}
+	return branch
+}
+
+func matchCondition(row map[string]any, c Condition) bool {
+	v := indirect(row[c.Column])
+	switch c.Op {
+	case OpNull:
+		return v == nil
+	case OpNotNull:
+		return v != nil
+	case OpIn:
+		if v == nil {
+			return false
+		}
+		values, _ := c.Value.([]any)
+		for _, want := range values {
+			if cmp, ok := compareValues(v, indirect(want)); ok && cmp == 0 {
+				return true
+			}
+		}
+		return false
+	}
+
+	// As in SQL, comparisons involving NULL never match.
+	want := indirect(c.Value)
+	if v == nil || want == nil {
+		return false
+	}
+	cmp, ok := compareValues(v, want)
+	if !ok {
+		return false
+	}
+	switch c.Op {
+	case OpEq:
+		return cmp == 0
+	case OpNotEq:
+		return cmp != 0
+	case OpLt:
+		return cmp < 0
+	case OpLte:
+		return cmp <= 0
+	case OpGt:
+		return cmp > 0
+	case OpGte:
+		return cmp >= 0
+	}
+	return false
+}
+
+// indirect dereferences pointer values, such as nullable *string fields, mapping
+// nil pointers to nil.
+func indirect(v any) any {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil
+	}
+	return rv.Interface()
+}
+
+// compareValues orders a and b numerically, chronologically or as strings.
+//
+// Whole numbers are compared exactly, so int64 ids above 2^53 stay distinct;
+// float64 is only used when a side is fractional. The second result is false
+// when the values cannot be ordered against each other.
+func compareValues(a, b any) (int, bool) {
+	if x, ok := toExactInt(a); ok {
+		if y, ok := toExactInt(b); ok {
+			return x.compare(y), true
+		}
+	}
+	if x, ok := toFloat(a); ok {
+		if y, ok := toFloat(b); ok {
+			return compareOrdered(x, y), true
+		}
+	}
+	if x, ok := a.(time.Time); ok {
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), true
+		}
+		return 0, false
+	}
+	if x, ok := a.(bool); ok {
+		y, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		if x == y {
+			return 0, true
+		}
+		return 1, true
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b)), true
+}
+
+// sortsBefore reports whether a strictly precedes b in the given direction, with
+// NULLs last.
+func sortsBefore(a, b any, desc bool) bool {
+	a, b = indirect(a), indirect(b)
+	if a == nil || b == nil {
+		return a != nil
+	}
+	cmp, ok := compareValues(a, b)
+	if !ok {
+		return false
+	}
+	if desc {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+func compareOrdered(x, y float64) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// exactInt is a whole number in sign-magnitude form, covering both the int64 and
+// the uint64 range.
+type exactInt struct {
+	neg bool
+	mag uint64
+}
+
+func (x exactInt) compare(y exactInt) int {
+	switch {
+	case x.neg != y.neg:
+		if x.neg {
+			return -1
+		}
+		return 1
+	case x.mag == y.mag:
+		return 0
+	case (x.mag < y.mag) != x.neg:
+		return -1
+	default:
+		return 1
+	}
+}
+
+func signedInt(n int64) exactInt {
+	if n < 0 {
+		return exactInt{neg: true, mag: uint64(-(n + 1)) + 1}
+	}
+	return exactInt{mag: uint64(n)}
+}
+
+// toExactInt converts integers, whole floats and integral json.Numbers.
+func toExactInt(v any) (exactInt, bool) {
+	switch x := v.(type) {
+	case int:
+		return signedInt(int64(x)), true
+	case int8:
+		return signedInt(int64(x)), true
+	case int16:
+		return signedInt(int64(x)), true
+	case int32:
+		return signedInt(int64(x)), true
+	case int64:
+		return signedInt(x), true
+	case uint:
+		return exactInt{mag: uint64(x)}, true
+	case uint8:
+		return exactInt{mag: uint64(x)}, true
+	case uint16:
+		return exactInt{mag: uint64(x)}, true
+	case uint32:
+		return exactInt{mag: uint64(x)}, true
+	case uint64:
+		return exactInt{mag: x}, true
+	case float32:
+		return wholeFloat(float64(x))
+	case float64:
+		return wholeFloat(x)
+	case json.Number:
+		if n, err := x.Int64(); err == nil {
+			return signedInt(n), true
+		}
+		if n, err := strconv.ParseUint(string(x), 10, 64); err == nil {
+			return exactInt{mag: n}, true
+		}
+		if f, err := x.Float64(); err == nil {
+			return wholeFloat(f)
+		}
+	}
+	return exactInt{}, false
+}
+
+func wholeFloat(f float64) (exactInt, bool) {
+	if f != math.Trunc(f) || math.Abs(f) >= 1<<64 {
+		return exactInt{}, false
+	}
+	if f < 0 {
+		return exactInt{neg: true, mag: uint64(-f)}, true
+	}
+	return exactInt{mag: uint64(f)}, true
+}
+
+func toFloat(v any) (float64, bool) {
+	switch x := v.(type) {
+	case int:
+		return float64(x), true
+	case int8:
+		return float64(x), true
+	case int16:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case uint:
+		return float64(x), true
+	case uint8:
+		return float64(x), true
+	case uint16:
+		return float64(x), true
+	case uint32:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	case float32:
+		return float64(x), true
+	case float64:
+		return x, true
+	case json.Number:
+		f, err := strconv.ParseFloat(string(x), 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,168 @@
+package database
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+type queryUser struct {
+	ID    int64   `db:"id"`
+	Name  string  `db:"name"`
+	Role  string  `db:"role"`
+	Age   int     `db:"age"`
+	Email *string `db:"email"`
+}
+
+func (queryUser) TableName() string { return "users" }
+
+// queryUsers seeds a memory connection and returns a function building queries on it.
+func queryUsers(t *testing.T) func() *Record[queryUser] {
+	t.Helper()
+	prev := Default()
+	t.Cleanup(func() { Use(prev) })
+	Use(NewMemoryConnection())
+
+	email := "ada@example.com"
+	for _, u := range []queryUser{
+		{Name: "Ada", Role: "admin", Age: 36, Email: &email},
+		{Name: "Bob", Role: "user", Age: 17},
+		{Name: "Cy", Role: "user", Age: 52},
+		{Name: "Di", Role: "guest", Age: 29},
+	} {
+		if err := Model[queryUser]().Create(&u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return Model[queryUser]
+}
+
+func names(t *testing.T, q *Record[queryUser]) []string {
+	t.Helper()
+	users, err := q.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := []string{}
+	for _, u := range users {
+		out = append(out, u.Name)
+	}
+	return out
+}
+
+func TestMemoryWhereOperators(t *testing.T) {
+	users := queryUsers(t)
+	for _, tc := range []struct {
+		op    string
+		value any
+		want  []string
+	}{
+		{OpEq, 36, []string{"Ada"}},
+		{OpNotEq, 36, []string{"Bob", "Cy", "Di"}},
+		{OpLt, 29, []string{"Bob"}},
+		{OpLte, 29, []string{"Bob", "Di"}},
+		{OpGt, 36, []string{"Cy"}},
+		{OpGte, 36, []string{"Ada", "Cy"}},
+	} {
+		if got := names(t, users().Where("age", tc.op, tc.value)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("age %s %v = %v, want %v", tc.op, tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestMemoryWhereIn(t *testing.T) {
+	users := queryUsers(t)
+	if got := names(t, users().WhereIn("role", []any{"admin", "guest"})); !reflect.DeepEqual(got, []string{"Ada", "Di"}) {
+		t.Errorf("role in (admin, guest) = %v", got)
+	}
+	if got := names(t, users().WhereIn("age", []any{17, float64(52), "29"})); !reflect.DeepEqual(got, []string{"Bob", "Cy", "Di"}) {
+		t.Errorf("age in (17, 52.0, \"29\") = %v", got)
+	}
+	if got := names(t, users().WhereIn("role", []any{})); len(got) != 0 {
+		t.Errorf("role in () = %v", got)
+	}
+}
+
+func TestMemoryWhereNull(t *testing.T) {
+	users := queryUsers(t)
+	if got := names(t, users().WhereNull("email")); !reflect.DeepEqual(got, []string{"Bob", "Cy", "Di"}) {
+		t.Errorf("email is null = %v", got)
+	}
+	if got := names(t, users().WhereNotNull("email")); !reflect.DeepEqual(got, []string{"Ada"}) {
+		t.Errorf("email is not null = %v", got)
+	}
+	// As in SQL, comparing with NULL never matches.
+	if got := names(t, users().Where("email", OpNotEq, "x")); !reflect.DeepEqual(got, []string{"Ada"}) {
+		t.Errorf("email != x = %v", got)
+	}
+}
+
+func TestMemoryOrWhere(t *testing.T) {
+	users := queryUsers(t)
+	// role = user AND age > 18 OR role = admin reads as (role = user AND age > 18) OR role = admin.
+	q := users().Where("role", OpEq, "user").Where("age", OpGt, 18).OrWhere("role", OpEq, "admin")
+	if got := names(t, q); !reflect.DeepEqual(got, []string{"Ada", "Cy"}) {
+		t.Errorf("got %v", got)
+	}
+	if got := names(t, users().OrWhere("name", OpEq, "Di")); !reflect.DeepEqual(got, []string{"Di"}) {
+		t.Errorf("leading OrWhere = %v", got)
+	}
+}
+
+func TestMemoryWhereRejectsUnknownOperator(t *testing.T) {
+	users := queryUsers(t)
+	if _, err := users().Where("age", "like", 1).Get(); err == nil {
+		t.Error("unsupported operator: no error")
+	}
+}
+
+func TestMemoryLargeIDsStayDistinct(t *testing.T) {
+	prev := Default()
+	t.Cleanup(func() { Use(prev) })
+	Use(NewMemoryConnection())
+
+	for _, u := range []queryUser{
+		{ID: 9007199254740992, Name: "Even"},
+		{ID: 9007199254740993, Name: "Odd"},
+	} {
+		if err := Model[queryUser]().Create(&u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := names(t, Model[queryUser]().Where("id", OpEq, int64(9007199254740993))); !reflect.DeepEqual(got, []string{"Odd"}) {
+		t.Errorf("id = ...993: %v", got)
+	}
+	if got := names(t, Model[queryUser]().Where("id", OpLt, json.Number("9007199254740993"))); !reflect.DeepEqual(got, []string{"Even"}) {
+		t.Errorf("id < ...993: %v", got)
+	}
+	if got := names(t, Model[queryUser]().WhereIn("id", []any{uint64(9007199254740992)})); !reflect.DeepEqual(got, []string{"Even"}) {
+		t.Errorf("id in (...992): %v", got)
+	}
+	u, ok, err := Model[queryUser]().Where("name", OpNotEq, "").Find(int64(9007199254740993))
+	if err != nil || !ok || u.Name != "Odd" {
+		t.Errorf("Find(...993) = %+v, %v, %v", u, ok, err)
+	}
+}
+
+func TestCompareValues(t *testing.T) {
+	for _, tc := range []struct {
+		a, b any
+		want int
+	}{
+		{int64(9007199254740993), int64(9007199254740992), 1},
+		{int64(-1), uint64(math.MaxUint64), -1},
+		{uint64(math.MaxUint64), int64(math.MaxInt64), 1},
+		{int64(math.MinInt64), int64(math.MinInt64 + 1), -1},
+		{float64(3), int(3), 0},
+		{json.Number("9007199254740993"), int64(9007199254740993), 0},
+		{float64(2.5), int(2), 1},
+		{json.Number("2.5"), float64(2.5), 0},
+		{-3, -2, -1},
+	} {
+		if got, ok := compareValues(tc.a, tc.b); !ok || got != tc.want {
+			t.Errorf("compareValues(%#v, %#v) = %d, %v; want %d", tc.a, tc.b, got, ok, tc.want)
+		}
+	}
+}
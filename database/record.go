@@ -10,10 +10,16 @@ type TableNamer interface {
 	TableName() string
 }
 
+// PrimaryKeyer is implemented by models whose primary key column is not "id".
+type PrimaryKeyer interface {
+	PrimaryKey() string
+}
+
 type Record[T any] struct {
 	conn  Connection
 	table string
 	pk    string
+	where []Condition
 }
 
 func Model[T any]() *Record[T] {
@@ -26,7 +32,7 @@ func Model[T any]() *Record[T] {
 	}
 
 	table := defaultTableName(zero)
-	return &Record[T]{conn: conn, table: table, pk: "id"}
+	return &Record[T]{conn: conn, table: table, pk: defaultPrimaryKey(zero)}
 }
 
 func (r *Record[T]) Table(name string) *Record[T] {
@@ -36,8 +42,23 @@ func (r *Record[T]) Table(name string) *Record[T] {
 	return r
 }
 
+// PrimaryKey sets the primary key column, for models keyed by "uuid", "user_id" and
+// the like. The struct field mapped to that column holds the record's id.
+//
+// Create lets connections implementing KeyInserter, such as MemoryConnection,
+// generate the key; with other connections set it before Create. Find, Save and
+// Delete address rows through Filterer, Updater and Deleter.
+func (r *Record[T]) PrimaryKey(col string) *Record[T] {
+	if col = strings.TrimSpace(col); col != "" {
+		r.pk = col
+	}
+	return r
+}
+
+// Find returns the record with the given primary key, provided it also matches the
+// query's conditions.
 func (r *Record[T]) Find(id any) (T, bool, error) {
-	row, ok, err := r.conn.Find(r.table, id)
+	row, ok, err := r.findRow(id)
 	if err != nil {
 		var zero T
 		return zero, false, err
@@ -54,7 +75,16 @@ func (r *Record[T]) Find(id any) (T, bool, error) {
 	return v, true, nil
 }
 
+// FindFirst returns the first record matching the query's conditions, or the
+// table's first record when there are none.
 func (r *Record[T]) FindFirst() (T, bool, error) {
+	if len(r.where) > 0 {
+		return r.First()
+	}
+	if _, ok := r.conn.(Filterer); ok {
+		return r.First()
+	}
+
 	row, ok, err := r.conn.First(r.table)
 	if err != nil {
 		var zero T
@@ -72,8 +102,44 @@ func (r *Record[T]) FindFirst() (T, bool, error) {
 	return v, true, nil
 }
 
+// All returns the records matching the query's conditions, or every record when
+// there are none.
 func (r *Record[T]) All() ([]T, error) {
-	rows, err := r.conn.All(r.table)
+	return r.Get()
+}
+
+// Where returns a copy of the query filtered by column op value, where op is one of
+// =, !=, <, <=, >, >=. Conditions chain with AND.
+//
+//	users, err := database.Model[User]().Where("age", ">=", 18).WhereNotNull("email").Get()
+func (r *Record[T]) Where(column, op string, value any) *Record[T] {
+	return r.with(Condition{Column: column, Op: op, Value: value})
+}
+
+// OrWhere is like Where but starts an OR branch: a.Where(...).OrWhere(...) matches
+// rows satisfying either side.
+func (r *Record[T]) OrWhere(column, op string, value any) *Record[T] {
+	return r.with(Condition{Column: column, Op: op, Value: value, Or: true})
+}
+
+// WhereIn filters rows whose column equals one of values. An empty list matches nothing.
+func (r *Record[T]) WhereIn(column string, values []any) *Record[T] {
+	return r.with(Condition{Column: column, Op: OpIn, Value: append([]any(nil), values...)})
+}
+
+// WhereNull filters rows whose column is NULL (or missing, for the memory connection).
+func (r *Record[T]) WhereNull(column string) *Record[T] {
+	return r.with(Condition{Column: column, Op: OpNull})
+}
+
+// WhereNotNull filters rows whose column is not NULL.
+func (r *Record[T]) WhereNotNull(column string) *Record[T] {
+	return r.with(Condition{Column: column, Op: OpNotNull})
+}
+
+// Get returns the records matching the query's conditions.
+func (r *Record[T]) Get() ([]T, error) {
+	rows, err := r.filter()
 	if err != nil {
 		return nil, err
 	}
@@ -88,6 +154,71 @@ func (r *Record[T]) All() ([]T, error) {
 	return out, nil
 }
 
+// First returns the first record matching the query's conditions.
+func (r *Record[T]) First() (T, bool, error) {
+	var zero T
+	rows, err := r.filter()
+	if err != nil || len(rows) == 0 {
+		return zero, false, err
+	}
+	v, err := mapToStruct[T](rows[0])
+	if err != nil {
+		return zero, false, err
+	}
+	return v, true, nil
+}
+
+// FirstBy returns the record matching the query's conditions with the lowest
+// (dir "asc") or highest (dir "desc") value in column. NULLs sort last and ties keep
+// the connection's order.
+func (r *Record[T]) FirstBy(column, dir string) (T, bool, error) {
+	var zero T
+	desc := false
+	switch strings.ToLower(strings.TrimSpace(dir)) {
+	case "", "asc":
+	case "desc":
+		desc = true
+	default:
+		return zero, false, fmt.Errorf("record: invalid sort direction %q", dir)
+	}
+
+	rows, err := r.filter()
+	if err != nil || len(rows) == 0 {
+		return zero, false, err
+	}
+	best := rows[0]
+	for _, row := range rows[1:] {
+		if sortsBefore(row[column], best[column], desc) {
+			best = row
+		}
+	}
+	v, err := mapToStruct[T](best)
+	if err != nil {
+		return zero, false, err
+	}
+	return v, true, nil
+}
+
+func (r *Record[T]) with(c Condition) *Record[T] {
+	cp := *r
+	cp.where = append(r.where[:len(r.where):len(r.where)], c)
+	return &cp
+}
+
+func (r *Record[T]) filter() ([]map[string]any, error) {
+	if f, ok := r.conn.(Filterer); ok {
+		return f.Filter(r.table, r.pk, r.where)
+	}
+	if err := checkConditions(r.where); err != nil {
+		return nil, err
+	}
+	rows, err := r.conn.All(r.table)
+	if err != nil {
+		return nil, err
+	}
+	return filterRows(rows, r.where), nil
+}
+
 func (r *Record[T]) Create(v *T) error {
 	if v == nil {
 		return fmt.Errorf("record: value is nil")
@@ -97,32 +228,158 @@ func (r *Record[T]) Create(v *T) error {
 	if err != nil {
 		return err
 	}
-	id, err := r.conn.Insert(r.table, row)
+	// A zero id means "let the connection assign one".
+	if _, ok := getID(*v, r.pk); !ok {
+		delete(row, r.pk)
+	}
+
+	var id any
+	if ki, ok := r.conn.(KeyInserter); ok {
+		id, err = ki.InsertKey(r.table, r.pk, row)
+	} else if r.pk == "id" {
+		id, err = r.conn.Insert(r.table, row)
+	} else {
+		// Insert's id belongs to an "id" column, not to r.pk.
+		if _, ok := row[r.pk]; !ok {
+			return fmt.Errorf("record: connection %T cannot generate %s; set it before Create", r.conn, r.pk)
+		}
+		_, err = r.conn.Insert(r.table, row)
+		return err
+	}
 	if err != nil {
 		return err
 	}
-	setID(v, id)
+	setID(v, r.pk, id)
 	return nil
 }
 
+// Save writes the whole record back by id.
+//
+// Records with a version field (`db:",version"` or an integer Version field) use
+// optimistic locking: the update only applies if the stored version is unchanged,
+// the version is incremented on success, and ErrStaleData is returned otherwise.
+// This needs a connection implementing Updater.
 func (r *Record[T]) Save(v *T) error {
 	if v == nil {
 		return fmt.Errorf("record: value is nil")
 	}
 
-	id, ok := getID(*v)
+	id, ok := getID(*v, r.pk)
 	if !ok {
-		return fmt.Errorf("record: missing id")
+		return fmt.Errorf("record: missing %s", r.pk)
 	}
 	row, err := structToMap(*v)
 	if err != nil {
 		return err
 	}
-	return r.conn.Update(r.table, id, row)
+
+	column, version, index, versioned := versionField(*v)
+	if r.pk == "id" && !versioned {
+		return r.conn.Update(r.table, id, row)
+	}
+	return r.saveWhere(v, id, row, column, version, index, versioned)
+}
+
+// UpdateColumns sets only the given columns on the record with id, leaving the
+// others untouched. Unlike Save it cannot clobber columns that were not loaded.
+func (r *Record[T]) UpdateColumns(id any, changes map[string]any) error {
+	affected, err := r.UpdateWhere(r.byKey(id), changes)
+	if err != nil {
+		return err
+	}
+	if affected == 0 && len(changes) > 0 {
+		return fmt.Errorf("record: row not found")
+	}
+	return nil
+}
+
+// UpdateWhere sets only the given columns on every row matching conds and returns
+// the number of affected rows. The primary key cannot be changed.
+func (r *Record[T]) UpdateWhere(conds []Condition, changes map[string]any) (int64, error) {
+	if _, ok := changes[r.pk]; ok {
+		return 0, fmt.Errorf("record: cannot update %s", r.pk)
+	}
+	u, err := r.updater()
+	if err != nil {
+		return 0, err
+	}
+	return u.UpdateWhere(r.table, conds, changes)
+}
+
+func (r *Record[T]) updater() (Updater, error) {
+	u, ok := r.conn.(Updater)
+	if !ok {
+		return nil, fmt.Errorf("record: connection %T does not support conditional updates", r.conn)
+	}
+	return u, nil
 }
 
 func (r *Record[T]) Delete(id any) error {
-	return r.conn.Delete(r.table, id)
+	if r.pk == "id" {
+		return r.conn.Delete(r.table, id)
+	}
+	d, ok := r.conn.(Deleter)
+	if !ok {
+		return fmt.Errorf("record: connection %T does not support conditional deletes", r.conn)
+	}
+	_, err := d.DeleteWhere(r.table, r.byKey(id))
+	return err
+}
+
+// saveWhere is Save through Updater, for versioned records and models with a
+// custom primary key, which the connection's id-based Update cannot express.
+func (r *Record[T]) saveWhere(v *T, id any, row map[string]any, column string, version int64, index int, versioned bool) error {
+	delete(row, r.pk)
+	where := r.byKey(id)
+	if versioned {
+		row[column] = version + 1
+		where = append(where, Condition{Column: column, Op: OpEq, Value: version})
+	}
+
+	u, err := r.updater()
+	if err != nil {
+		return err
+	}
+	affected, err := u.UpdateWhere(r.table, where, row)
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		if versioned {
+			return ErrStaleData
+		}
+		return fmt.Errorf("record: row not found")
+	}
+	if versioned {
+		reflect.ValueOf(v).Elem().Field(index).SetInt(version + 1)
+	}
+	return nil
+}
+
+func (r *Record[T]) findRow(id any) (map[string]any, bool, error) {
+	if r.pk == "id" && len(r.where) == 0 {
+		return r.conn.Find(r.table, id)
+	}
+	cp := *r
+	cp.where = andAll(r.where, Condition{Column: r.pk, Op: OpEq, Value: id})
+	rows, err := cp.filter()
+	if err != nil || len(rows) == 0 {
+		return nil, false, err
+	}
+	return rows[0], true, nil
+}
+
+func (r *Record[T]) byKey(id any) []Condition {
+	return []Condition{{Column: r.pk, Op: OpEq, Value: id}}
+}
+
+func defaultPrimaryKey[T any](v T) string {
+	if pk, ok := any(v).(PrimaryKeyer); ok {
+		if col := strings.TrimSpace(pk.PrimaryKey()); col != "" {
+			return col
+		}
+	}
+	return "id"
 }
 
 func defaultTableName[T any](v T) string {
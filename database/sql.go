@@ -0,0 +1,380 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PoolConfig holds database/sql connection pool settings.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// SQLOption configures a SQL connection pool.
+type SQLOption func(*PoolConfig)
+
+// MaxOpenConns limits the number of open connections (0 means unlimited).
+func MaxOpenConns(n int) SQLOption {
+	return func(c *PoolConfig) { c.MaxOpenConns = n }
+}
+
+// MaxIdleConns limits the number of idle connections kept in the pool.
+func MaxIdleConns(n int) SQLOption {
+	return func(c *PoolConfig) { c.MaxIdleConns = n }
+}
+
+// ConnMaxLifetime limits how long a connection may be reused (0 means forever).
+func ConnMaxLifetime(d time.Duration) SQLOption {
+	return func(c *PoolConfig) { c.ConnMaxLifetime = d }
+}
+
+// PoolConfigFromEnv returns pool settings from the environment, falling back to defaults:
+//
+//	DB_MAX_OPEN_CONNS     (default 25)
+//	DB_MAX_IDLE_CONNS     (default 10)
+//	DB_CONN_MAX_LIFETIME  (default 5m, Go duration syntax)
+func PoolConfigFromEnv() PoolConfig {
+	cfg := PoolConfig{
+		MaxOpenConns:    25,
+		MaxIdleConns:    10,
+		ConnMaxLifetime: 5 * time.Minute,
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("DB_MAX_OPEN_CONNS"))); err == nil {
+		cfg.MaxOpenConns = n
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(os.Getenv("DB_MAX_IDLE_CONNS"))); err == nil {
+		cfg.MaxIdleConns = n
+	}
+	if d, err := time.ParseDuration(strings.TrimSpace(os.Getenv("DB_CONN_MAX_LIFETIME"))); err == nil {
+		cfg.ConnMaxLifetime = d
+	}
+	return cfg
+}
+
+// SQLConnection is a Connection backed by database/sql.
+//
+// Its id-based methods (Find, Update, Delete) address the "id" column; Record
+// reaches tables keyed by other columns through InsertKey, Filter, UpdateWhere
+// and DeleteWhere.
+//
+// The driver itself is not bundled: applications register it with a blank import
+// (e.g. _ "github.com/lib/pq") and pass its name to OpenSQL.
+type SQLConnection struct {
+	DB *sql.DB
+
+	driver string
+}
+
+// OpenSQL opens and pings a SQL database, applying pool settings.
+//
+// Pool settings start from PoolConfigFromEnv and are then overridden by opts.
+func OpenSQL(driver, dsn string, opts ...SQLOption) (*SQLConnection, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := PoolConfigFromEnv()
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &SQLConnection{DB: db, driver: driver}, nil
+}
+
+// Stats returns connection pool statistics, e.g. for a health check endpoint.
+func (c *SQLConnection) Stats() sql.DBStats {
+	return c.DB.Stats()
+}
+
+// Close closes the underlying database.
+func (c *SQLConnection) Close() error {
+	return c.DB.Close()
+}
+
+// Query runs a raw query and returns each row as a column -> value map.
+func (c *SQLConnection) Query(query string, args ...any) ([]map[string]any, error) {
+	return c.query(query, args...)
+}
+
+// Exec runs a raw statement and returns the number of affected rows.
+func (c *SQLConnection) Exec(query string, args ...any) (int64, error) {
+	res, err := c.DB.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (c *SQLConnection) Find(table string, id any) (map[string]any, bool, error) {
+	q := fmt.Sprintf("SELECT * FROM %s WHERE %s = %s LIMIT 1", c.quote(table), c.quote("id"), c.placeholder(1))
+	return c.queryOne(q, id)
+}
+
+// First returns the row with the lowest id.
+func (c *SQLConnection) First(table string) (map[string]any, bool, error) {
+	q := fmt.Sprintf("SELECT * FROM %s ORDER BY %s LIMIT 1", c.quote(table), c.quote("id"))
+	return c.queryOne(q)
+}
+
+// All returns every row, ordered by id.
+func (c *SQLConnection) All(table string) ([]map[string]any, error) {
+	q := fmt.Sprintf("SELECT * FROM %s ORDER BY %s", c.quote(table), c.quote("id"))
+	return c.query(q)
+}
+
+// Filter returns the rows matching where, ordered by pk when it is not empty.
+func (c *SQLConnection) Filter(table, pk string, where []Condition) ([]map[string]any, error) {
+	clause, args, err := c.whereClause(where, 0)
+	if err != nil {
+		return nil, err
+	}
+	q := fmt.Sprintf("SELECT * FROM %s%s", c.quote(table), clause)
+	if pk != "" {
+		q += " ORDER BY " + c.quote(pk)
+	}
+	return c.query(q, args...)
+}
+
+func (c *SQLConnection) Insert(table string, row map[string]any) (any, error) {
+	return c.InsertKey(table, "id", row)
+}
+
+// InsertKey inserts row and returns its pk value. When the row has no pk the
+// database generates it, so the column must be auto-increment (or identity).
+func (c *SQLConnection) InsertKey(table, pk string, row map[string]any) (any, error) {
+	skip := ""
+	if row[pk] == nil {
+		skip = pk
+	}
+	cols := sortedColumns(row, skip)
+	names := make([]string, len(cols))
+	marks := make([]string, len(cols))
+	args := make([]any, len(cols))
+	for i, col := range cols {
+		names[i] = c.quote(col)
+		marks[i] = c.placeholder(i + 1)
+		args[i] = row[col]
+	}
+
+	q := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)", c.quote(table), strings.Join(names, ", "), strings.Join(marks, ", "))
+	if c.postgres() {
+		var id any
+		if err := c.DB.QueryRow(q+" RETURNING "+c.quote(pk), args...).Scan(&id); err != nil {
+			return nil, err
+		}
+		return id, nil
+	}
+
+	res, err := c.DB.Exec(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	if id := row[pk]; id != nil {
+		return id, nil
+	}
+	return res.LastInsertId()
+}
+
+func (c *SQLConnection) Update(table string, id any, row map[string]any) error {
+	cols := sortedColumns(row, "id")
+	sets := make([]string, len(cols))
+	args := make([]any, 0, len(cols)+1)
+	for i, col := range cols {
+		sets[i] = c.quote(col) + " = " + c.placeholder(i+1)
+		args = append(args, row[col])
+	}
+	args = append(args, id)
+
+	q := fmt.Sprintf("UPDATE %s SET %s WHERE %s = %s", c.quote(table), strings.Join(sets, ", "), c.quote("id"), c.placeholder(len(args)))
+	_, err := c.DB.Exec(q, args...)
+	return err
+}
+
+func (c *SQLConnection) UpdateWhere(table string, where []Condition, changes map[string]any) (int64, error) {
+	if len(changes) == 0 {
+		return 0, nil
+	}
+	cols := sortedColumns(changes, "")
+	sets := make([]string, len(cols))
+	args := make([]any, 0, len(cols))
+	for i, col := range cols {
+		sets[i] = c.quote(col) + " = " + c.placeholder(i+1)
+		args = append(args, changes[col])
+	}
+	clause, whereArgs, err := c.whereClause(where, len(args))
+	if err != nil {
+		return 0, err
+	}
+
+	q := fmt.Sprintf("UPDATE %s SET %s%s", c.quote(table), strings.Join(sets, ", "), clause)
+	return c.Exec(q, append(args, whereArgs...)...)
+}
+
+func (c *SQLConnection) Delete(table string, id any) error {
+	q := fmt.Sprintf("DELETE FROM %s WHERE %s = %s", c.quote(table), c.quote("id"), c.placeholder(1))
+	_, err := c.DB.Exec(q, id)
+	return err
+}
+
+func (c *SQLConnection) DeleteWhere(table string, where []Condition) (int64, error) {
+	clause, args, err := c.whereClause(where, 0)
+	if err != nil {
+		return 0, err
+	}
+	return c.Exec(fmt.Sprintf("DELETE FROM %s%s", c.quote(table), clause), args...)
+}
+
+func (c *SQLConnection) queryOne(q string, args ...any) (map[string]any, bool, error) {
+	rows, err := c.query(q, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(rows) == 0 {
+		return nil, false, nil
+	}
+	return rows[0], true, nil
+}
+
+func (c *SQLConnection) query(q string, args ...any) ([]map[string]any, error) {
+	rows, err := c.DB.Query(q, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanRows(rows)
+}
+
+// whereClause renders where as " WHERE ..." with placeholders numbered after offset;
+// it is empty when there are no conditions.
+func (c *SQLConnection) whereClause(where []Condition, offset int) (string, []any, error) {
+	if len(where) == 0 {
+		return "", nil, nil
+	}
+	if err := checkConditions(where); err != nil {
+		return "", nil, err
+	}
+
+	var b strings.Builder
+	var args []any
+	for i, cond := range where {
+		if i > 0 {
+			if cond.Or {
+				b.WriteString(" OR ")
+			} else {
+				b.WriteString(" AND ")
+			}
+		}
+		col := c.quote(cond.Column)
+		switch cond.Op {
+		case OpNull:
+			b.WriteString(col + " IS NULL")
+		case OpNotNull:
+			b.WriteString(col + " IS NOT NULL")
+		case OpIn:
+			values := cond.Value.([]any)
+			if len(values) == 0 {
+				// IN () is invalid SQL; an empty set matches nothing.
+				b.WriteString("1 = 0")
+				continue
+			}
+			marks := make([]string, len(values))
+			for j, v := range values {
+				args = append(args, v)
+				marks[j] = c.placeholder(offset + len(args))
+			}
+			b.WriteString(col + " IN (" + strings.Join(marks, ", ") + ")")
+		default:
+			op := cond.Op
+			if op == OpNotEq {
+				op = "<>"
+			}
+			args = append(args, cond.Value)
+			b.WriteString(col + " " + op + " " + c.placeholder(offset+len(args)))
+		}
+	}
+	return " WHERE " + b.String(), args, nil
+}
+
+func scanRows(rows *sql.Rows) ([]map[string]any, error) {
+	cols, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []map[string]any
+	for rows.Next() {
+		vals := make([]any, len(cols))
+		ptrs := make([]any, len(cols))
+		for i := range vals {
+			ptrs[i] = &vals[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]any, len(cols))
+		for i, col := range cols {
+			// Many drivers return text columns as []byte.
+			if b, ok := vals[i].([]byte); ok {
+				row[col] = string(b)
+				continue
+			}
+			row[col] = vals[i]
+		}
+		out = append(out, row)
+	}
+	return out, rows.Err()
+}
+
+// sortedColumns returns the columns of row except skip, in a stable order.
+func sortedColumns(row map[string]any, skip string) []string {
+	cols := make([]string, 0, len(row))
+	for col := range row {
+		if col == skip {
+			continue
+		}
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+	return cols
+}
+
+func (c *SQLConnection) postgres() bool {
+	switch c.driver {
+	case "postgres", "pgx", "pq":
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *SQLConnection) placeholder(n int) string {
+	if c.postgres() {
+		return "$" + strconv.Itoa(n)
+	}
+	return "?"
+}
+
+func (c *SQLConnection) quote(ident string) string {
+	if c.driver == "mysql" {
+		return "`" + strings.ReplaceAll(ident, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(ident, `"`, `""`) + `"`
+}
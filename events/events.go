@@ -0,0 +1,148 @@
+// Package events dispatches application events to typed listeners.
+//
+// Events are plain values keyed by their Go type:
+//
+//	type UserRegistered struct{ User models.User }
+//
+//	events.Listen(func(e UserRegistered) error { ... })
+//	events.Subscribe[UserRegistered](SendWelcomeEmail{}) // may implement ShouldQueue
+//
+//	err := events.Dispatch(UserRegistered{User: u})
+package events
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/jimo-go/framework/queue"
+)
+
+// Listener handles events of type E.
+type Listener[E any] interface {
+	Handle(event E) error
+}
+
+// ListenerFunc adapts a function to a Listener.
+type ListenerFunc[E any] func(event E) error
+
+func (f ListenerFunc[E]) Handle(event E) error { return f(event) }
+
+// ShouldQueue marks listeners that run on the queue instead of inside Dispatch.
+type ShouldQueue interface {
+	ShouldQueue() bool
+}
+
+// Option configures a listener registration.
+type Option func(*registration)
+
+// Priority orders listeners: higher priorities run first (default 0).
+// Listeners with equal priority run in registration order.
+func Priority(n int) Option {
+	return func(r *registration) { r.priority = n }
+}
+
+// Queued runs the listener on the queue, like implementing ShouldQueue.
+func Queued() Option {
+	return func(r *registration) { r.queued = true }
+}
+
+type registration struct {
+	handle   func(event any) error
+	priority int
+	queued   bool
+	seq      int
+}
+
+var (
+	mu        sync.RWMutex
+	listeners = map[reflect.Type][]*registration{}
+	seq       int
+)
+
+// Listen registers fn for events of type E.
+func Listen[E any](fn func(event E) error, opts ...Option) {
+	Subscribe[E](ListenerFunc[E](fn), opts...)
+}
+
+// Subscribe registers l for events of type E.
+//
+// Listeners implementing ShouldQueue (returning true) are pushed onto the default
+// queue (queue.Push) when the event is dispatched.
+func Subscribe[E any](l Listener[E], opts ...Option) {
+	if l == nil {
+		panic("events: listener is nil")
+	}
+
+	r := &registration{
+		handle: func(event any) error { return l.Handle(event.(E)) },
+	}
+	if q, ok := l.(ShouldQueue); ok && q.ShouldQueue() {
+		r.queued = true
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(r)
+		}
+	}
+
+	t := reflect.TypeFor[E]()
+	mu.Lock()
+	defer mu.Unlock()
+	seq++
+	r.seq = seq
+	rs := append(listeners[t], r)
+	sort.SliceStable(rs, func(i, j int) bool {
+		if rs[i].priority != rs[j].priority {
+			return rs[i].priority > rs[j].priority
+		}
+		return rs[i].seq < rs[j].seq
+	})
+	listeners[t] = rs
+}
+
+// Forget removes every listener for events of type E.
+func Forget[E any]() {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(listeners, reflect.TypeFor[E]())
+}
+
+// HasListeners reports whether any listener is registered for events of type E.
+func HasListeners[E any]() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return len(listeners[reflect.TypeFor[E]()]) > 0
+}
+
+// Dispatch sends event to its listeners in priority order.
+//
+// Synchronous listeners run before Dispatch returns; queued listeners are pushed
+// onto the default queue and run later. Every listener is invoked even if an
+// earlier one fails; the returned error joins listener and push failures.
+func Dispatch(event any) error {
+	if event == nil {
+		return errors.New("events: event is nil")
+	}
+
+	mu.RLock()
+	rs := listeners[reflect.TypeOf(event)]
+	mu.RUnlock()
+
+	var errs []error
+	for _, r := range rs {
+		handle := r.handle
+		if r.queued {
+			if err := queue.Push(func() error { return handle(event) }); err != nil {
+				errs = append(errs, fmt.Errorf("events: queue %T listener: %w", event, err))
+			}
+			continue
+		}
+		if err := handle(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
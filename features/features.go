@@ -0,0 +1,71 @@
+package features
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	mu        sync.RWMutex
+	overrides = make(map[string]bool)
+)
+
+// IsEnabled reports whether the named feature flag is on.
+//
+// A runtime override set via Set wins; otherwise the flag is read from the
+// FEATURE_<NAME> environment variable (e.g. "new-checkout" -> FEATURE_NEW_CHECKOUT).
+// Unknown flags are off.
+func IsEnabled(name string) bool {
+	mu.RLock()
+	on, ok := overrides[name]
+	mu.RUnlock()
+	if ok {
+		return on
+	}
+
+	v, ok := os.LookupEnv(EnvKey(name))
+	if !ok {
+		return false
+	}
+	b, err := strconv.ParseBool(strings.TrimSpace(v))
+	return err == nil && b
+}
+
+// Set overrides a feature flag at runtime.
+func Set(name string, on bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	overrides[name] = on
+}
+
+// Enable turns a feature flag on at runtime.
+func Enable(name string) { Set(name, true) }
+
+// Disable turns a feature flag off at runtime.
+func Disable(name string) { Set(name, false) }
+
+// Reset removes the runtime override for a feature flag, falling back to the environment.
+func Reset(name string) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(overrides, name)
+}
+
+// EnvKey returns the environment variable backing a feature flag.
+func EnvKey(name string) string {
+	var b strings.Builder
+	b.WriteString("FEATURE_")
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case r >= 'a' && r <= 'z':
+			b.WriteRune(r - 'a' + 'A')
+		case (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,45 @@
+package features
+
+import "testing"
+
+func TestToggleFlag(t *testing.T) {
+	const name = "new-checkout"
+	t.Cleanup(func() { Reset(name) })
+
+	t.Setenv("FEATURE_NEW_CHECKOUT", "")
+	if IsEnabled(name) {
+		t.Fatal("flag on with an empty env value")
+	}
+
+	t.Setenv("FEATURE_NEW_CHECKOUT", "true")
+	if !IsEnabled(name) {
+		t.Fatal("flag off with FEATURE_NEW_CHECKOUT=true")
+	}
+
+	Disable(name)
+	if IsEnabled(name) {
+		t.Fatal("runtime override did not win over the environment")
+	}
+	Enable(name)
+	if !IsEnabled(name) {
+		t.Fatal("Enable did not turn the flag on")
+	}
+
+	Reset(name)
+	t.Setenv("FEATURE_NEW_CHECKOUT", "0")
+	if IsEnabled(name) {
+		t.Fatal("Reset did not fall back to the environment")
+	}
+}
+
+func TestEnvKey(t *testing.T) {
+	for name, want := range map[string]string{
+		"new-checkout": "FEATURE_NEW_CHECKOUT",
+		"Beta2":        "FEATURE_BETA2",
+		" dark mode ":  "FEATURE_DARK_MODE",
+	} {
+		if got := EnvKey(name); got != want {
+			t.Errorf("EnvKey(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
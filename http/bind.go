@@ -0,0 +1,372 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// BindAll fills v from the path parameters, then the query string, then the JSON body.
+//
+// Later sources win, so the precedence is body > query > path. Path parameters
+// and query values are matched against the `param` and `query` struct tags
+// respectively, falling back to the field's JSON or db name. The body is decoded
+// like MustBind (unknown fields are rejected) but may be empty.
+//
+// On failure, it panics with an HTTPError (400).
+func (c *Context) BindAll(v any) {
+	if err := bindValues(v, "param", false, func(key string) ([]string, bool) {
+		val, ok := c.params.get(key)
+		return []string{val}, ok
+	}); err != nil {
+		panic(HTTPError{Status: http.StatusBadRequest, Message: "Invalid path parameter", Err: err})
+	}
+
+	c.BindQuery(v)
+
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		panic(HTTPError{Status: http.StatusBadRequest, Message: "Invalid JSON", Err: err})
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	c.MustBind(v)
+}
+
+// BindPatch decodes a JSON object body into v like MustBind and reports which
+// top-level keys the client actually sent, so a PATCH handler can tell "set to
+// zero" from "not included" and apply only the present fields:
+//
+//	var in UpdatePost
+//	present, err := ctx.BindPatch(&in)
+//	if err != nil {
+//		panic(err)
+//	}
+//	changes, _ := database.PatchColumns(in, present)
+//	err = database.Model[Post]().UpdateColumns(id, changes)
+//
+// Keys are JSON names. Errors are HTTPErrors (400), so they can be re-panicked.
+func (c *Context) BindPatch(v any) (map[string]bool, error) {
+	if v == nil {
+		return nil, HTTPError{Status: http.StatusBadRequest, Message: "Invalid JSON", Err: errors.New("bind target is nil")}
+	}
+	if c.Request.Body == nil {
+		return nil, HTTPError{Status: http.StatusBadRequest, Message: "Invalid JSON", Err: errors.New("empty body")}
+	}
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return nil, HTTPError{Status: http.StatusBadRequest, Message: "Invalid JSON", Err: err}
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(body, &keys); err != nil {
+		return nil, HTTPError{Status: http.StatusBadRequest, Message: "Invalid JSON", Err: err}
+	}
+	if keys == nil {
+		return nil, HTTPError{Status: http.StatusBadRequest, Message: "Invalid JSON", Err: errors.New("body must be a JSON object")}
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.DisallowUnknownFields()
+	if c.useNumber() {
+		dec.UseNumber()
+	}
+	if err := dec.Decode(v); err != nil {
+		return nil, HTTPError{Status: http.StatusBadRequest, Message: "Invalid JSON", Err: err}
+	}
+
+	present := make(map[string]bool, len(keys))
+	for k := range keys {
+		present[k] = true
+	}
+	return present, nil
+}
+
+// BindQuery fills v from the query string, matching the `query` struct tag or the
+// field's JSON or db name. Values are converted like BindAll.
+//
+// On failure, it panics with an HTTPError (400).
+func (c *Context) BindQuery(v any) {
+	query := c.Request.URL.Query()
+	if err := bindValues(v, "query", false, func(key string) ([]string, bool) {
+		vals, ok := query[key]
+		return vals, ok
+	}); err != nil {
+		panic(HTTPError{Status: http.StatusBadRequest, Message: "Invalid query parameter", Err: err})
+	}
+}
+
+// BindForm fills v from an application/x-www-form-urlencoded or
+// multipart/form-data body, matching the `form` struct tag or the field's JSON
+// or db name. Values are converted like BindAll; checkbox values "on" and "off"
+// are accepted for bools. Query string values are ignored.
+//
+// For multipart bodies, *multipart.FileHeader and []*multipart.FileHeader fields
+// receive the uploaded files (see Context.MultipartForm for the limits).
+//
+// Other content types fail with 415, a body over the upload size cap with 413
+// and malformed bodies or values with 400, all as panicked HTTPErrors.
+func (c *Context) BindForm(v any) {
+	mt, _, _ := mime.ParseMediaType(c.Request.Header.Get("Content-Type"))
+
+	var (
+		values map[string][]string
+		files  map[string][]*multipart.FileHeader
+	)
+	switch mt {
+	case "application/x-www-form-urlencoded":
+		if err := c.Request.ParseForm(); err != nil {
+			panic(HTTPError{Status: http.StatusBadRequest, Message: "Invalid form", Err: err})
+		}
+		values = c.Request.PostForm
+	case "multipart/form-data":
+		form, err := c.MultipartForm()
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				panic(HTTPError{Status: http.StatusRequestEntityTooLarge, Message: "Upload too large", Err: err})
+			}
+			panic(HTTPError{Status: http.StatusBadRequest, Message: "Invalid form", Err: err})
+		}
+		values, files = form.Value, form.File
+	default:
+		panic(HTTPError{Status: http.StatusUnsupportedMediaType, Message: "Content-Type must be a form"})
+	}
+
+	if err := bindValues(v, "form", false, func(key string) ([]string, bool) {
+		vals, ok := values[key]
+		return vals, ok
+	}); err != nil {
+		panic(HTTPError{Status: http.StatusBadRequest, Message: "Invalid form field", Err: err})
+	}
+	if len(files) > 0 {
+		bindFiles(reflect.ValueOf(v).Elem(), files)
+	}
+}
+
+// BindHeader fills v from request headers using `header:"X-Api-Version"` struct tags.
+//
+// Only tagged fields are bound; header names are case-insensitive. Missing headers
+// leave the field untouched. Slice fields receive every value of a repeated header;
+// other fields take the first. Values are converted like BindAll.
+//
+// On failure, it panics with an HTTPError (400).
+func (c *Context) BindHeader(v any) {
+	if err := bindValues(v, "header", true, func(key string) ([]string, bool) {
+		vals := c.Request.Header.Values(key)
+		return vals, len(vals) > 0
+	}); err != nil {
+		panic(HTTPError{Status: http.StatusBadRequest, Message: "Invalid header", Err: err})
+	}
+}
+
+// bindValues sets the fields of the struct pointed to by v from string values.
+//
+// Each field is looked up by its tag (e.g. `query:"page"`), falling back to its JSON
+// name and then its Go name unless tagOnly is set. Fields tagged "-" are skipped;
+// embedded structs are walked.
+func bindValues(v any, tag string, tagOnly bool, lookup func(key string) ([]string, bool)) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("bind target must be a non-nil pointer to a struct")
+	}
+	return bindStruct(rv.Elem(), tag, tagOnly, lookup)
+}
+
+func bindStruct(sv reflect.Value, tag string, tagOnly bool, lookup func(key string) ([]string, bool)) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		f := st.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fv := sv.Field(i)
+
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get(tag) == "" {
+			if err := bindStruct(fv, tag, tagOnly, lookup); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, tagged := f.Tag.Lookup(tag); tagOnly && !tagged {
+			continue
+		}
+		if isFileType(f.Type) {
+			continue // set by bindFiles
+		}
+		key := fieldKey(f, tag)
+		if key == "" {
+			continue
+		}
+		vals, ok := lookup(key)
+		if !ok || len(vals) == 0 {
+			continue
+		}
+		if tag == "form" {
+			vals = checkboxValues(f.Type, vals)
+		}
+		if err := setValue(fv, vals); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	return nil
+}
+
+var (
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+func isFileType(t reflect.Type) bool {
+	return t == fileHeaderType || t == fileHeadersType
+}
+
+// bindFiles sets the *multipart.FileHeader and []*multipart.FileHeader fields of
+// sv, keyed like the `form` fields of bindStruct.
+func bindFiles(sv reflect.Value, files map[string][]*multipart.FileHeader) {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		f := st.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		fv := sv.Field(i)
+
+		if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("form") == "" {
+			bindFiles(fv, files)
+			continue
+		}
+		if !isFileType(f.Type) {
+			continue
+		}
+		key := fieldKey(f, "form")
+		fhs := files[key]
+		if key == "" || len(fhs) == 0 {
+			continue
+		}
+		if f.Type == fileHeaderType {
+			fv.Set(reflect.ValueOf(fhs[0]))
+		} else {
+			fv.Set(reflect.ValueOf(fhs))
+		}
+	}
+}
+
+// fieldKey names the value bound to f: its tag, else its json or db tag name, else
+// the field name. "-" in the first tag present skips the field.
+func fieldKey(f reflect.StructField, tag string) string {
+	for _, t := range []string{tag, "json", "db"} {
+		name, ok := f.Tag.Lookup(t)
+		if !ok {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return f.Name
+}
+
+// checkboxValues maps the "on" and "off" an HTML checkbox submits to "true" and
+// "false" when t is a bool (or a pointer to or slice of bools). Only BindForm
+// accepts them; query strings and headers keep strconv.ParseBool's syntax.
+func checkboxValues(t reflect.Type, vals []string) []string {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Bool {
+		return vals
+	}
+	out := make([]string, len(vals))
+	for i, s := range vals {
+		switch s {
+		case "on":
+			s = "true"
+		case "off":
+			s = "false"
+		}
+		out[i] = s
+	}
+	return out
+}
+
+// setValue converts vals into fv. Slices take every value; other kinds take the first.
+func setValue(fv reflect.Value, vals []string) error {
+	if fv.Kind() == reflect.Pointer {
+		elem := reflect.New(fv.Type().Elem())
+		if err := setValue(elem.Elem(), vals); err != nil {
+			return err
+		}
+		fv.Set(elem)
+		return nil
+	}
+
+	if fv.Kind() == reflect.Slice && fv.Type().Elem().Kind() != reflect.Uint8 {
+		out := reflect.MakeSlice(fv.Type(), len(vals), len(vals))
+		for i, s := range vals {
+			if err := setScalar(out.Index(i), s); err != nil {
+				return err
+			}
+		}
+		fv.Set(out)
+		return nil
+	}
+
+	return setScalar(fv, vals[0])
+}
+
+func setScalar(fv reflect.Value, s string) error {
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", s)
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", s)
+		}
+		fv.SetFloat(n)
+	default:
+		// Fall back to JSON for types such as time.Time.
+		if err := json.Unmarshal([]byte(strconv.Quote(s)), fv.Addr().Interface()); err != nil {
+			return fmt.Errorf("unsupported value %q for %s", s, fv.Type())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package http
+
+import (
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+type signupForm struct {
+	Name     string `form:"name"`
+	Age      int    `form:"age"`
+	Terms    bool   `form:"terms"`
+	News     *bool  `form:"news"`
+	Tags     []string
+	Avatar   *multipart.FileHeader `form:"avatar"`
+	Internal string                `form:"-"`
+}
+
+func bindFormRouter(t *testing.T, got *signupForm) *Router {
+	t.Helper()
+	r := NewRouter()
+	r.Post("/upload", func(c *Context) {
+		c.BindForm(got)
+		c.String(http.StatusOK, "ok")
+	})
+	return r
+}
+
+func TestBindFormURLEncoded(t *testing.T) {
+	var got signupForm
+	r := bindFormRouter(t, &got)
+
+	body := url.Values{
+		"name": {"Ada"}, "age": {"36"}, "terms": {"on"}, "news": {"off"},
+		"Tags": {"a", "b"}, "Internal": {"x"},
+	}
+	req := httptest.NewRequest(http.MethodPost, "/upload?name=Query", strings.NewReader(body.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d %q", rec.Code, rec.Body.String())
+	}
+	if got.Name != "Ada" || got.Age != 36 || !got.Terms || got.News == nil || *got.News {
+		t.Errorf("got %+v", got)
+	}
+	if len(got.Tags) != 2 || got.Tags[1] != "b" || got.Internal != "" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestBindFormTagFallbacks(t *testing.T) {
+	var got struct {
+		UserName string `db:"user_name"`
+		Email    string `json:"email" db:"email_address"`
+		Hidden   string `json:"-" db:"hidden"`
+	}
+	r := NewRouter()
+	r.Post("/users", func(c *Context) {
+		c.BindForm(&got)
+		c.String(http.StatusOK, "ok")
+	})
+
+	body := url.Values{"user_name": {"ada"}, "email": {"ada@example.com"}, "email_address": {"x"}, "hidden": {"x"}}
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d %q", rec.Code, rec.Body.String())
+	}
+	if got.UserName != "ada" || got.Email != "ada@example.com" || got.Hidden != "" {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestBindFormMultipart(t *testing.T) {
+	var got signupForm
+	r := bindFormRouter(t, &got)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, multipartRequest(t, nil, map[string]string{"name": "Ada", "terms": "on"}))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d %q", rec.Code, rec.Body.String())
+	}
+	if got.Name != "Ada" || !got.Terms || got.Avatar != nil {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestBindFormMultipartFileAndFields(t *testing.T) {
+	var got signupForm
+	r := bindFormRouter(t, &got)
+
+	req := multipartRequest(t, map[string]string{"avatar": "png"}, map[string]string{"name": "Ada", "age": "36"})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d %q", rec.Code, rec.Body.String())
+	}
+	if got.Name != "Ada" || got.Age != 36 {
+		t.Errorf("got %+v", got)
+	}
+	if got.Avatar == nil || got.Avatar.Filename != "avatar.txt" || got.Avatar.Size != 3 {
+		t.Errorf("avatar = %+v", got.Avatar)
+	}
+}
+
+func TestBindFormErrors(t *testing.T) {
+	var got signupForm
+	r := bindFormRouter(t, &got)
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("age=old"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("bad int: got %d", rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(`{"name":"Ada"}`))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("json body: got %d", rec.Code)
+	}
+}
+
+func TestCheckboxValuesOnlyInForms(t *testing.T) {
+	r := NewRouter()
+	r.Get("/", func(c *Context) {
+		var q struct {
+			Active bool `query:"active"`
+		}
+		c.BindQuery(&q)
+		c.String(http.StatusOK, "ok")
+	})
+
+	if rec := serve(r, http.MethodGet, "/?active=on"); rec.Code != http.StatusBadRequest {
+		t.Errorf("query active=on: got %d, want 400", rec.Code)
+	}
+	if rec := serve(r, http.MethodGet, "/?active=true"); rec.Code != http.StatusOK {
+		t.Errorf("query active=true: got %d", rec.Code)
+	}
+}
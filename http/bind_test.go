@@ -0,0 +1,62 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const bigID = "9007199254740993" // 2^53 + 1: not representable as float64
+
+func bindPayload(t *testing.T, useNumber bool, body string) map[string]any {
+	t.Helper()
+	r := NewRouter()
+	r.UseJSONNumber(useNumber)
+	var got map[string]any
+	r.Post("/", func(c *Context) {
+		c.MustBind(&got)
+		c.String(http.StatusOK, "ok")
+	})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	return got
+}
+
+func TestUseJSONNumberKeepsLargeIntegers(t *testing.T) {
+	got := bindPayload(t, true, `{"id":`+bigID+`,"count":1}`)
+	id, ok := got["id"].(json.Number)
+	if !ok || id.String() != bigID {
+		t.Fatalf("id = %#v, want json.Number %s", got["id"], bigID)
+	}
+	if n, ok := got["count"].(json.Number); !ok || n.String() != "1" {
+		t.Fatalf("count = %#v, want json.Number 1", got["count"])
+	}
+}
+
+func TestDefaultJSONNumbersAreFloats(t *testing.T) {
+	got := bindPayload(t, false, `{"id":`+bigID+`}`)
+	f, ok := got["id"].(float64)
+	if !ok {
+		t.Fatalf("id = %#v, want float64", got["id"])
+	}
+	if int64(f) == 9007199254740993 {
+		t.Fatal("float64 unexpectedly kept the large id exact")
+	}
+}
+
+func TestMustBindTypedInt64(t *testing.T) {
+	r := NewRouter()
+	var got struct {
+		ID int64 `json:"id"`
+	}
+	r.Post("/", func(c *Context) { c.MustBind(&got) })
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"id":`+bigID+`}`)))
+	if got.ID != 9007199254740993 {
+		t.Fatalf("ID = %d", got.ID)
+	}
+}
@@ -0,0 +1,101 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+)
+
+// ResponseBuffer holds a response in memory until the Buffered middleware flushes it.
+//
+// It implements http.ResponseWriter; Flush calls are ignored while buffering.
+type ResponseBuffer struct {
+	w      http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (b *ResponseBuffer) Header() http.Header { return b.w.Header() }
+
+func (b *ResponseBuffer) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *ResponseBuffer) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+// Flush is a no-op: nothing reaches the client before the handler chain returns.
+func (b *ResponseBuffer) Flush() {}
+
+// Status returns the buffered status code (200 if none was written).
+func (b *ResponseBuffer) Status() int {
+	if b.status == 0 {
+		return http.StatusOK
+	}
+	return b.status
+}
+
+// SetStatus replaces the buffered status code.
+func (b *ResponseBuffer) SetStatus(status int) { b.status = status }
+
+// Body returns the buffered body. The slice is only valid until the next write.
+func (b *ResponseBuffer) Body() []byte { return b.body.Bytes() }
+
+// SetBody replaces the buffered body.
+func (b *ResponseBuffer) SetBody(body []byte) {
+	b.body.Reset()
+	b.body.Write(body)
+}
+
+func (b *ResponseBuffer) flush() {
+	h := b.w.Header()
+	h.Del("Content-Length")
+	if b.body.Len() > 0 {
+		h.Set("Content-Length", strconv.Itoa(b.body.Len()))
+	}
+	b.w.WriteHeader(b.Status())
+	_, _ = b.body.WriteTo(b.w)
+}
+
+// Buffered holds the response of the rest of the chain in memory and sends it once
+// the chain returns, so middleware registered after it can inspect and rewrite the
+// status, headers and body through Context.Buffer:
+//
+//	func Envelope(next jimohttp.HandlerFunc) jimohttp.HandlerFunc {
+//		return func(ctx *jimohttp.Context) {
+//			next(ctx)
+//			buf := ctx.Buffer()
+//			buf.SetBody(append(append([]byte(`{"data":`), buf.Body()...), '}'))
+//		}
+//	}
+//
+//	r.Use(jimohttp.Buffered(), Envelope)
+//
+// The whole body is kept in memory and nothing is sent before the handler returns,
+// so avoid it on large downloads and streaming responses. Content-Length is set
+// from the final body. If the chain panics, the buffer is discarded and the error
+// response is written instead.
+func Buffered() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) {
+			orig := ctx.ResponseWriter
+			buf := &ResponseBuffer{w: orig}
+			outer := ctx.buffer
+			ctx.ResponseWriter, ctx.buffer = buf, buf
+			next(ctx)
+			ctx.ResponseWriter, ctx.buffer = orig, outer
+			buf.flush()
+		}
+	}
+}
+
+// Buffer returns the response buffer installed by Buffered, or nil.
+func (c *Context) Buffer() *ResponseBuffer {
+	return c.buffer
+}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCatchAll(t *testing.T) {
+	r := NewRouter()
+	r.Get("/files/{path...}", func(c *Context) { c.String(http.StatusOK, "files:"+c.Param("path")) })
+	r.Get("/files/readme", func(c *Context) { c.String(http.StatusOK, "readme") })
+	r.Get("/docs/{version}/{page...}", func(c *Context) {
+		c.String(http.StatusOK, c.Param("version")+":"+c.Param("page"))
+	})
+
+	for path, want := range map[string]string{
+		"/files/a/b/c.txt":   "files:a/b/c.txt",
+		"/files/c.txt":       "files:c.txt",
+		"/files":             "files:",
+		"/files/readme":      "readme",
+		"/files/readme/more": "files:readme/more",
+		"/docs/v2/guide/api": "v2:guide/api",
+	} {
+		rec := serve(r, http.MethodGet, path)
+		if rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Errorf("GET %s = %d %q, want %q", path, rec.Code, rec.Body.String(), want)
+		}
+	}
+}
+
+func TestCatchAllMustBeLast(t *testing.T) {
+	defer func() {
+		if rec := recover(); rec == nil {
+			t.Fatal("registering a catch-all before the last segment did not panic")
+		}
+	}()
+	NewRouter().Get("/files/{path...}/raw", func(*Context) {})
+}
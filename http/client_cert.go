@@ -0,0 +1,16 @@
+package http
+
+import "crypto/x509"
+
+// ClientCert returns the client certificate of a mutual TLS connection, or nil
+// when the request did not come over TLS or the certificate was not verified
+// against the server's tls.Config.ClientCAs (e.g. with tls.RequireAnyClientCert).
+//
+// Behind a TLS-terminating proxy the router never sees the certificate.
+func (c *Context) ClientCert() *x509.Certificate {
+	state := c.Request.TLS
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return state.VerifiedChains[0][0]
+}
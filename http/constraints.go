@@ -0,0 +1,106 @@
+package http
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// paramConstraint restricts the values a {name:constraint} route param matches.
+type paramConstraint struct {
+	raw   string
+	match func(string) bool
+}
+
+// String returns the constraint as written in the route, or "" for nil.
+func (c *paramConstraint) String() string {
+	if c == nil {
+		return ""
+	}
+	return c.raw
+}
+
+// constraintTypes are the named constraints usable as {param:type}.
+var constraintTypes = map[string]func(string) bool{
+	"int": func(s string) bool {
+		_, err := strconv.ParseInt(s, 10, 64)
+		return err == nil
+	},
+	"uint": func(s string) bool {
+		_, err := strconv.ParseUint(s, 10, 64)
+		return err == nil
+	},
+	"alpha": func(s string) bool { return allBytes(s, isAlpha) },
+	"alnum": func(s string) bool {
+		return allBytes(s, func(b byte) bool { return isAlpha(b) || isDigit(b) })
+	},
+	"slug": func(s string) bool {
+		return allBytes(s, func(b byte) bool { return isAlpha(b) || isDigit(b) || b == '-' || b == '_' })
+	},
+	"uuid": func(s string) bool { return uuidPattern.MatchString(s) },
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// parseConstraint parses the part of a param segment after the colon.
+//
+// A bare word must be a known type (int, uint, alpha, alnum, slug, uuid); anything
+// else is a regular expression that must match the whole segment.
+func parseConstraint(raw string) (*paramConstraint, error) {
+	if raw == "" {
+		return nil, fmt.Errorf("empty constraint")
+	}
+	if allBytes(raw, isAlpha) {
+		fn, ok := constraintTypes[raw]
+		if !ok {
+			return nil, fmt.Errorf("unknown constraint type %q", raw)
+		}
+		return &paramConstraint{raw: raw, match: fn}, nil
+	}
+
+	re, err := regexp.Compile("^(?:" + raw + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid constraint pattern %q: %v", raw, err)
+	}
+	return &paramConstraint{raw: raw, match: re.MatchString}, nil
+}
+
+// parseParamSegment splits "{name}" or "{name:constraint}" into its parts.
+func parseParamSegment(seg string) (name, constraint string, ok bool) {
+	if len(seg) < 3 || seg[0] != '{' || seg[len(seg)-1] != '}' {
+		return "", "", false
+	}
+	inner := seg[1 : len(seg)-1]
+	name, constraint, _ = strings.Cut(inner, ":")
+	if name == "" || strings.ContainsAny(name, "/{}") {
+		return "", "", false
+	}
+	return name, constraint, true
+}
+
+// parseCatchAll returns the name of a "{name...}" catch-all segment.
+func parseCatchAll(seg string) (string, bool) {
+	name, constraint, ok := parseParamSegment(seg)
+	if !ok || constraint != "" || !strings.HasSuffix(name, "...") {
+		return "", false
+	}
+	name = strings.TrimSuffix(name, "...")
+	return name, name != ""
+}
+
+func allBytes(s string, fn func(byte) bool) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if !fn(s[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlpha(b byte) bool { return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') }
+
+func isDigit(b byte) bool { return b >= '0' && b <= '9' }
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestIntConstraint(t *testing.T) {
+	r := NewRouter()
+	r.Get("/users/{id:int}", func(c *Context) {
+		id, err := c.ParamInt("id")
+		if err != nil {
+			t.Errorf("ParamInt: %v", err)
+		}
+		c.JSON(http.StatusOK, id)
+	})
+	r.Post("/users/{id:int}", func(c *Context) {})
+
+	for path, want := range map[string]int{
+		"/users/42":                   http.StatusOK,
+		"/users/-7":                   http.StatusOK,
+		"/users/0":                    http.StatusOK,
+		"/users/abc":                  http.StatusNotFound,
+		"/users/4.2":                  http.StatusNotFound,
+		"/users/99999999999999999999": http.StatusNotFound, // overflows int64
+		"/users/":                     http.StatusNotFound, // empty segment
+		"/users//":                    http.StatusNotFound,
+	} {
+		if rec := serve(r, http.MethodGet, path); rec.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, want)
+		}
+	}
+
+	if rec := serve(r, http.MethodGet, "/users/-7"); strings.TrimSpace(rec.Body.String()) != "-7" {
+		t.Errorf("ParamInt(-7) = %q", rec.Body.String())
+	}
+	// A value failing the constraint matches no route under any method: 404, not 405.
+	if rec := serve(r, http.MethodPut, "/users/abc"); rec.Code != http.StatusNotFound {
+		t.Errorf("PUT /users/abc = %d, want 404", rec.Code)
+	}
+	if rec := serve(r, http.MethodPut, "/users/42"); rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("PUT /users/42 = %d, want 405", rec.Code)
+	}
+}
+
+func TestParamIntErrors(t *testing.T) {
+	r := NewRouter()
+	var errs []error
+	r.Get("/n/{v}", func(c *Context) {
+		_, err := c.ParamInt("v")
+		errs = append(errs, err)
+		_, err = c.ParamInt("missing")
+		errs = append(errs, err)
+	})
+	serve(r, http.MethodGet, "/n/99999999999999999999")
+	if len(errs) != 2 || errs[0] == nil || errs[1] == nil {
+		t.Fatalf("errors = %v, want overflow and missing param errors", errs)
+	}
+}
+
+func TestConstraintValidatedAtRegistration(t *testing.T) {
+	for _, path := range []string{"/u/{id:integer}", "/u/{id:[0-9}"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s registered without panicking", path)
+				}
+			}()
+			NewRouter().Get(path, func(*Context) {})
+		}()
+	}
+}
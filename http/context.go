@@ -1,10 +1,16 @@
 package http
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
 
 	"github.com/jimo-go/framework/validation"
 )
@@ -14,11 +20,24 @@ type Context struct {
 	ResponseWriter http.ResponseWriter
 	Request        *http.Request
 
-	params map[string]string
+	params routeParams
 	views  *viewEngine
+	router *Router
 
-	session *Session
-	csrf    string
+	session  *Session
+	sessions *SessionManager // set by the Sessions middleware
+	csrf     string
+
+	requestID string
+
+	values   map[string]any
+	deferred []func()
+	halted   bool
+	buffer   *ResponseBuffer
+	writeErr error // set when a write failed because the client disconnected
+	input    *input
+
+	multipartErr error // cached MultipartForm parse failure
 }
 
 // HTTPError is a typed error used to propagate HTTP failures through panics.
@@ -39,6 +58,22 @@ func (e HTTPError) Error() string {
 
 func (e HTTPError) Unwrap() error { return e.Err }
 
+// routeParams are the params captured for a request. names is shared with the
+// matched route; only values belong to the request.
+type routeParams struct {
+	names  []string
+	values []string
+}
+
+func (p routeParams) get(name string) (string, bool) {
+	for i, n := range p.names {
+		if n == name {
+			return p.values[i], true
+		}
+	}
+	return "", false
+}
+
 // NewContext creates a new request context.
 func NewContext(w http.ResponseWriter, r *http.Request, views *viewEngine) *Context {
 	return &Context{ResponseWriter: w, Request: r, views: views}
@@ -46,10 +81,136 @@ func NewContext(w http.ResponseWriter, r *http.Request, views *viewEngine) *Cont
 
 // Param returns a route parameter by name.
 func (c *Context) Param(name string) string {
-	if c.params == nil {
-		return ""
+	v, _ := c.params.get(name)
+	return v
+}
+
+// ParamOk returns a route parameter by name and whether the route captured it.
+func (c *Context) ParamOk(name string) (string, bool) {
+	return c.params.get(name)
+}
+
+// ParamInt returns a route parameter parsed as a base-10 int. It fails when the
+// param is missing, empty, not a number or out of range.
+//
+// With an {id:int} route the value is already known to be numeric, so only
+// overflow of a 32-bit int remains possible.
+func (c *Context) ParamInt(name string) (int, error) {
+	v, ok := c.params.get(name)
+	if !ok {
+		return 0, fmt.Errorf("http: route param %q not found", name)
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("http: route param %q: %w", name, err)
+	}
+	return n, nil
+}
+
+// HasParam reports whether the route captured a parameter with the given name.
+func (c *Context) HasParam(name string) bool {
+	_, ok := c.params.get(name)
+	return ok
+}
+
+// Pagination reads the page and per_page query parameters.
+//
+// page is at least 1; perPage falls back to defaultPer when missing or invalid and
+// is clamped to maxPer (when maxPer > 0). Malformed or negative values never
+// error, they fall back to the defaults.
+func (c *Context) Pagination(defaultPer, maxPer int) (page, perPage int) {
+	if defaultPer < 1 {
+		defaultPer = 1
+	}
+	q := c.Request.URL.Query()
+
+	page, err := strconv.Atoi(strings.TrimSpace(q.Get("page")))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	perPage, err = strconv.Atoi(strings.TrimSpace(q.Get("per_page")))
+	if err != nil || perPage < 1 {
+		perPage = defaultPer
+	}
+	if maxPer > 0 && perPage > maxPer {
+		perPage = maxPer
+	}
+	return page, perPage
+}
+
+// Halt stops the rest of the middleware chain and the handler from running.
+//
+// It is the non-panicking way for middleware to end a request early after writing
+// a response itself (a cache hit, a redirect):
+//
+//	ctx.String(http.StatusOK, cached)
+//	ctx.Halt()
+//	return
+//
+// Halt does not unwind the caller; code after it still runs, and calling next
+// afterwards is a no-op. Middleware further out still completes normally, so
+// errors should keep using HTTPError panics.
+func (c *Context) Halt() {
+	c.halted = true
+}
+
+// Halted reports whether Halt was called.
+func (c *Context) Halted() bool {
+	return c.halted
+}
+
+// Set stores a request-scoped value on the context.
+func (c *Context) Set(key string, value any) {
+	if c.values == nil {
+		c.values = make(map[string]any)
 	}
-	return c.params[name]
+	c.values[key] = value
+}
+
+// Get returns a request-scoped value stored with Set, or nil.
+func (c *Context) Get(key string) any {
+	return c.values[key]
+}
+
+// Defer registers fn to run after the response has been sent.
+//
+// Deferred functions run once the handler, its middleware (including the session
+// save) and error handling have finished, so the response is already committed:
+// they must not use ResponseWriter, and Request.Context() is canceled by then.
+// They run sequentially in registration order on a separate goroutine, so they do
+// not delay the client; panics are recovered and passed to Router.OnError (logged
+// when no hook is set). Use the queue package for work that must survive a
+// shutdown or be retried.
+func (c *Context) Defer(fn func()) {
+	if fn == nil {
+		return
+	}
+	c.deferred = append(c.deferred, fn)
+}
+
+func (c *Context) runDeferred() {
+	if len(c.deferred) == 0 {
+		return
+	}
+	fns := c.deferred
+	c.deferred = nil
+	rep := c.router.errorReporter()
+	go func() {
+		for _, fn := range fns {
+			func() {
+				defer func() {
+					if rec := recover(); rec != nil {
+						if rep == nil {
+							log.Printf("http: deferred function panicked: %v", rec)
+							return
+						}
+						rep.report(&PanicError{Value: rec, Stack: debug.Stack()}, c)
+					}
+				}()
+				fn()
+			}()
+		}
+	}()
 }
 
 // Session returns the current request session.
@@ -59,54 +220,228 @@ func (c *Context) Session() *Session {
 	return c.session
 }
 
+// SessionID returns the current session's ID (see Session.ID), or "" without the
+// Sessions middleware.
+func (c *Context) SessionID() string {
+	return c.session.ID()
+}
+
 // CSRFToken returns the CSRF token for the current session.
 //
-// It is empty unless Sessions+CSRF middleware is enabled.
+// It is empty unless Sessions+CSRF middleware is enabled, and follows token
+// rotation by Session.Regenerate during the request.
 func (c *Context) CSRFToken() string {
+	if c.csrf != "" && c.session != nil {
+		return c.session.CSRF
+	}
 	return c.csrf
 }
 
+// Validate validates a struct against a set of rules without panicking.
+//
+// It mirrors validation.Validate: the bool is true when validation failed.
+func (c *Context) Validate(v any, rules validation.Rules) (validation.Error, bool) {
+	return validation.Validate(v, rules)
+}
+
 // MustValidate validates a struct against a set of rules.
 //
 // On failure, it panics with an HTTPError (422) and attaches the validation error as Err.
 func (c *Context) MustValidate(v any, rules validation.Rules) {
-	err, failed := validation.Validate(v, rules)
+	err, failed := c.Validate(v, rules)
 	if !failed {
 		return
 	}
 	panic(HTTPError{Status: http.StatusUnprocessableEntity, Message: "Validation failed", Err: err})
 }
 
-// JSON writes a JSON response.
+// JSON writes a JSON response, wrapped in the router's envelope if one is set
+// (see Router.WrapJSON).
 func (c *Context) JSON(status int, data any) {
+	if c.router != nil {
+		if wrap := c.router.jsonWrapper(false); wrap != nil {
+			data = wrap(status, data)
+		}
+	}
+	c.JSONRaw(status, data)
+}
+
+// JSONRaw writes a JSON response without the router's envelope.
+func (c *Context) JSONRaw(status int, data any) {
 	c.ResponseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
 	c.ResponseWriter.WriteHeader(status)
-	if err := json.NewEncoder(c.ResponseWriter).Encode(data); err != nil {
+	if err := json.NewEncoder(c.ResponseWriter).Encode(data); err != nil && !c.wroteErr(err) {
 		panic(HTTPError{Status: http.StatusInternalServerError, Message: "Failed to encode JSON", Err: err})
 	}
 }
 
+// OK writes a 200 JSON response.
+func (c *Context) OK(data any) {
+	c.JSON(http.StatusOK, data)
+}
+
+// Created writes a 201 JSON response.
+func (c *Context) Created(data any) {
+	c.JSON(http.StatusCreated, data)
+}
+
 // String writes a plain-text response.
 func (c *Context) String(status int, text string) {
 	c.ResponseWriter.Header().Set("Content-Type", "text/plain; charset=utf-8")
 	c.ResponseWriter.WriteHeader(status)
-	_, _ = io.WriteString(c.ResponseWriter, text)
+	if _, err := io.WriteString(c.ResponseWriter, text); err != nil {
+		c.wroteErr(err)
+	}
+}
+
+// Redirect writes a redirect to url with status, which must be a 3xx code such
+// as http.StatusFound or http.StatusSeeOther (use the latter after a form POST).
+//
+// It panics with an HTTPError (500) for other statuses.
+func (c *Context) Redirect(status int, url string) {
+	if status < 300 || status > 399 {
+		panic(HTTPError{Status: http.StatusInternalServerError, Message: "Invalid redirect status", Err: fmt.Errorf("http: redirect status %d is not 3xx", status)})
+	}
+	c.ResponseWriter.Header().Set("Location", url)
+	c.ResponseWriter.WriteHeader(status)
+}
+
+// RedirectToRoute redirects to the named route (see Router.URL).
+//
+// It panics with an HTTPError (500) when no route has that name.
+func (c *Context) RedirectToRoute(name string, params map[string]string, status int) {
+	var url string
+	if c.router != nil {
+		url = c.router.URL(name, params)
+	}
+	if url == "" {
+		panic(HTTPError{Status: http.StatusInternalServerError, Message: "Unknown route", Err: fmt.Errorf("http: route %q not found", name)})
+	}
+	c.Redirect(status, url)
 }
 
 // View renders an HTML template from the configured views directory.
 //
-// On failure, it panics with an HTTPError (500).
+// The page is rendered before anything is written, so a failing template yields
+// a clean error response. On failure, it panics with an HTTPError (500) whose Err
+// is a *ViewError; in debug mode (Router.SetDebug) the message includes it.
 func (c *Context) View(name string, data any) {
 	if c.views == nil {
 		panic(HTTPError{Status: http.StatusInternalServerError, Message: "View engine is not configured"})
 	}
 
+	var buf bytes.Buffer
+	if err := c.views.Render(&buf, name, data); err != nil {
+		panic(c.viewFailed(err))
+	}
 	c.ResponseWriter.Header().Set("Content-Type", "text/html; charset=utf-8")
 	c.ResponseWriter.WriteHeader(http.StatusOK)
+	if _, err := buf.WriteTo(c.ResponseWriter); err != nil {
+		c.wroteErr(err)
+	}
+}
+
+func (c *Context) viewFailed(err error) HTTPError {
+	e := HTTPError{Status: http.StatusInternalServerError, Message: "Failed to render view", Err: err}
+	if c.debug() {
+		e.Message += ": " + err.Error()
+	}
+	return e
+}
+
+func (c *Context) debug() bool {
+	if c.router == nil {
+		return false
+	}
+	c.router.state.mu.RLock()
+	defer c.router.state.mu.RUnlock()
+	return c.router.state.debug
+}
 
-	if err := c.views.Render(c.ResponseWriter, name, data); err != nil {
-		panic(HTTPError{Status: http.StatusInternalServerError, Message: "Failed to render view", Err: err})
+func (c *Context) useNumber() bool {
+	if c.router == nil {
+		return false
 	}
+	c.router.state.mu.RLock()
+	defer c.router.state.mu.RUnlock()
+	return c.router.state.useNumber
+}
+
+// Fragment renders part of a view for partial page updates (e.g. HTMX swaps).
+//
+// name is "file#block": the block is a {{define}} or {{block}} in the view file and is
+// rendered on its own, without the rest of the page. Without "#block" the whole
+// file is rendered, which suits standalone partial templates.
+//
+// On failure, it panics with an HTTPError (500).
+func (c *Context) Fragment(name string, data any) {
+	if c.views == nil {
+		panic(HTTPError{Status: http.StatusInternalServerError, Message: "View engine is not configured"})
+	}
+	file, block, hasBlock := strings.Cut(name, "#")
+	if !hasBlock {
+		c.View(file, data)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := c.views.RenderBlock(&buf, file, block, data); err != nil {
+		panic(c.viewFailed(err))
+	}
+	c.ResponseWriter.Header().Set("Content-Type", "text/html; charset=utf-8")
+	c.ResponseWriter.WriteHeader(http.StatusOK)
+	if _, err := buf.WriteTo(c.ResponseWriter); err != nil {
+		c.wroteErr(err)
+	}
+}
+
+// ViewOrFragment renders only block of the view for HTMX requests (HX-Request: true)
+// and the full view otherwise. Boosted requests (HX-Boosted) get the full view, since
+// HTMX swaps the whole body for them. The response varies on HX-Request so caches
+// keep both versions apart.
+func (c *Context) ViewOrFragment(name, block string, data any) {
+	c.Vary("HX-Request")
+	if c.IsHTMX() && c.Request.Header.Get("HX-Boosted") != "true" {
+		c.Fragment(name+"#"+block, data)
+		return
+	}
+	c.View(name, data)
+}
+
+// Vary adds request headers to the response's Vary header, skipping ones already
+// listed. Call it whenever the response depends on a request header (Accept,
+// Accept-Encoding, Accept-Language, ...) so shared caches and CDNs keep the
+// representations apart. ViewOrFragment, for one, varies on HX-Request.
+func (c *Context) Vary(headers ...string) {
+	AddVary(c.ResponseWriter.Header(), headers...)
+}
+
+// AddVary is Context.Vary for middleware working on a plain http.Header.
+func AddVary(h http.Header, headers ...string) {
+	listed := make(map[string]bool)
+	for _, line := range h.Values("Vary") {
+		for _, v := range strings.Split(line, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				listed[http.CanonicalHeaderKey(v)] = true
+			}
+		}
+	}
+	if listed["*"] {
+		return
+	}
+	for _, name := range headers {
+		name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+		if name == "" || listed[name] {
+			continue
+		}
+		listed[name] = true
+		h.Add("Vary", name)
+	}
+}
+
+// IsHTMX reports whether the request was made by HTMX.
+func (c *Context) IsHTMX() bool {
+	return c.Request.Header.Get("HX-Request") == "true"
 }
 
 // MustBind decodes the JSON request body into v and validates that:
@@ -114,6 +449,8 @@ func (c *Context) View(name string, data any) {
 // - Unknown fields are rejected
 // - The body contains exactly one JSON value
 //
+// Numbers decoded into interface values are json.Number when Router.UseJSONNumber is enabled.
+//
 // On failure, it panics with an HTTPError (400).
 func (c *Context) MustBind(v any) {
 	if v == nil {
@@ -122,6 +459,9 @@ func (c *Context) MustBind(v any) {
 
 	dec := json.NewDecoder(c.Request.Body)
 	dec.DisallowUnknownFields()
+	if c.useNumber() {
+		dec.UseNumber()
+	}
 
 	if err := dec.Decode(v); err != nil {
 		panic(HTTPError{Status: http.StatusBadRequest, Message: "Invalid JSON", Err: err})
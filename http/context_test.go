@@ -0,0 +1,26 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestOKAndCreated(t *testing.T) {
+	r := NewRouter()
+	r.Get("/items", func(c *Context) { c.OK(Map{"items": []int{1}}) })
+	r.Post("/items", func(c *Context) { c.Created(Map{"id": 1}) })
+
+	rec := serve(r, http.MethodGet, "/items")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Fatalf("OK: %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var body map[string][]int
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil || len(body["items"]) != 1 {
+		t.Fatalf("OK body = %q (%v)", rec.Body.String(), err)
+	}
+
+	if rec := serve(r, http.MethodPost, "/items"); rec.Code != http.StatusCreated || rec.Body.String() != "{\"id\":1}\n" {
+		t.Fatalf("Created: %d %q", rec.Code, rec.Body.String())
+	}
+}
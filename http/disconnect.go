@@ -0,0 +1,53 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net"
+	"syscall"
+)
+
+// IsClientGone reports whether the client went away before the response was
+// complete: the request context was canceled, or a write failed with a broken pipe
+// or connection reset.
+//
+// Long-running handlers can check it to stop early. A panic caused by the
+// disconnect, such as a failed write, gets no error response and is not reported
+// to OnError; any other panic is handled as usual.
+func (c *Context) IsClientGone() bool {
+	if c.writeErr != nil {
+		return true
+	}
+	return errors.Is(c.Request.Context().Err(), context.Canceled)
+}
+
+// wroteErr records a failed response write and reports whether it was caused by a
+// client disconnect (in which case it should be ignored rather than raised).
+func (c *Context) wroteErr(err error) bool {
+	if err == nil {
+		return false
+	}
+	if isDisconnect(err) || errors.Is(c.Request.Context().Err(), context.Canceled) {
+		c.writeErr = err
+		return true
+	}
+	return false
+}
+
+// disconnectPanic reports whether rec was raised because the client went away:
+// a write already failed on a disconnect, or rec is an error (such as an HTTPError
+// wrapping one) caused by it.
+func (c *Context) disconnectPanic(rec any) bool {
+	if c.writeErr != nil {
+		return true
+	}
+	err, ok := rec.(error)
+	return ok && isDisconnect(err)
+}
+
+func isDisconnect(err error) bool {
+	return errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, context.Canceled)
+}
@@ -0,0 +1,52 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+)
+
+func canceledRequest(path string) *http.Request {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return httptest.NewRequest(http.MethodGet, path, nil).WithContext(ctx)
+}
+
+func TestPanicAfterCancelIsReported(t *testing.T) {
+	r := NewRouter()
+	var reported []error
+	r.OnError(func(err error, ctx *Context) { reported = append(reported, err) })
+	r.Get("/", func(c *Context) { panic("nil map write") })
+
+	r.ServeHTTP(httptest.NewRecorder(), canceledRequest("/"))
+	if len(reported) != 1 {
+		t.Fatalf("reported %v, want the panic", reported)
+	}
+	var pe *PanicError
+	if !errors.As(reported[0], &pe) || pe.Value != "nil map write" {
+		t.Errorf("reported %v", reported[0])
+	}
+}
+
+func TestDisconnectPanicIsNotReported(t *testing.T) {
+	r := NewRouter()
+	var reported []error
+	r.OnError(func(err error, ctx *Context) { reported = append(reported, err) })
+	r.Get("/", func(c *Context) {
+		panic(HTTPError{Status: http.StatusInternalServerError, Message: "write failed", Err: syscall.EPIPE})
+	})
+	r.Get("/canceled", func(c *Context) { panic(c.Request.Context().Err()) })
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Body.Len() != 0 {
+		t.Errorf("wrote %q for a disconnect", rec.Body.String())
+	}
+	r.ServeHTTP(httptest.NewRecorder(), canceledRequest("/canceled"))
+	if len(reported) != 0 {
+		t.Errorf("reported %v", reported)
+	}
+}
@@ -0,0 +1,268 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultDumpRedact lists the headers DumpHTTP masks unless DumpRedact is given.
+var DefaultDumpRedact = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization", "X-Api-Key", "X-Csrf-Token"}
+
+// DefaultDumpRedactFields lists the JSON and form body fields DumpHTTP masks unless
+// DumpRedactFields is given.
+var DefaultDumpRedactFields = []string{
+	"password", "password_confirmation", "current_password", "new_password",
+	"token", "access_token", "refresh_token", "secret", "api_key", "_token",
+}
+
+type dumpOptions struct {
+	out     io.Writer
+	redact  map[string]bool
+	fields  map[string]bool
+	maxBody int
+}
+
+// DumpOption configures DumpHTTP.
+type DumpOption func(*dumpOptions)
+
+// DumpRedact replaces the list of headers whose values are masked in the dump.
+func DumpRedact(headers ...string) DumpOption {
+	return func(o *dumpOptions) {
+		o.redact = make(map[string]bool, len(headers))
+		for _, h := range headers {
+			o.redact[http.CanonicalHeaderKey(strings.TrimSpace(h))] = true
+		}
+	}
+}
+
+// DumpRedactFields replaces the list of body fields whose values are masked in the
+// dump. Fields match case-insensitively at any depth of a JSON body and by name in
+// a urlencoded form.
+func DumpRedactFields(fields ...string) DumpOption {
+	return func(o *dumpOptions) {
+		o.fields = make(map[string]bool, len(fields))
+		for _, f := range fields {
+			o.fields[strings.ToLower(strings.TrimSpace(f))] = true
+		}
+	}
+}
+
+// DumpTo writes dumps to w instead of the standard logger.
+func DumpTo(w io.Writer) DumpOption {
+	return func(o *dumpOptions) { o.out = w }
+}
+
+// DumpMaxBody caps how many bytes of each body are printed (64KB by default).
+// Bodies are still passed through in full.
+func DumpMaxBody(n int) DumpOption {
+	return func(o *dumpOptions) { o.maxBody = n }
+}
+
+// DumpHTTP logs every request (method, URL, headers, body) and its response
+// (status, headers, body) for debugging. It is not a request logger: bodies are
+// buffered in memory and printed verbatim.
+//
+// Sensitive headers and body fields are masked (see DefaultDumpRedact and
+// DefaultDumpRedactFields); multipart bodies are not printed. Requests are only
+// dumped while the router is in debug mode (see Router.SetDebug, which Jimo
+// applications turn on for APP_DEBUG outside production), so the middleware
+// cannot leak data if left in.
+func DumpHTTP(opts ...DumpOption) Middleware {
+	o := dumpOptions{maxBody: 64 << 10}
+	DumpRedact(DefaultDumpRedact...)(&o)
+	DumpRedactFields(DefaultDumpRedactFields...)(&o)
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) {
+			if !ctx.debug() {
+				next(ctx)
+				return
+			}
+
+			req := ctx.Request
+			var reqBody []byte
+			if req.Body != nil && req.Body != http.NoBody {
+				b, err := io.ReadAll(req.Body)
+				_ = req.Body.Close()
+				if err != nil {
+					panic(HTTPError{Status: http.StatusBadRequest, Message: "Failed to read request body", Err: err})
+				}
+				reqBody = b
+				req.Body = io.NopCloser(bytes.NewReader(b))
+			}
+
+			orig := ctx.ResponseWriter
+			cw := &captureWriter{ResponseWriter: orig}
+			ctx.ResponseWriter = cw
+			start := time.Now()
+			defer func() {
+				ctx.ResponseWriter = orig
+				o.dump(req, reqBody, cw, time.Since(start))
+			}()
+
+			next(ctx)
+		}
+	}
+}
+
+func (o *dumpOptions) dump(req *http.Request, reqBody []byte, cw *captureWriter, elapsed time.Duration) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "--> %s %s %s\n", req.Method, req.URL.RequestURI(), req.Proto)
+	fmt.Fprintf(&b, "Host: %s\n", req.Host)
+	o.writeHeaders(&b, req.Header)
+	o.writeBody(&b, req.Header.Get("Content-Type"), reqBody)
+
+	status := cw.status
+	header := cw.header
+	if status == 0 {
+		// Nothing was written (yet): a panic unwinding past us, or an empty 200.
+		status = http.StatusOK
+		header = cw.Header()
+	}
+	fmt.Fprintf(&b, "<-- %d %s (%s)\n", status, http.StatusText(status), elapsed.Round(time.Microsecond))
+	o.writeHeaders(&b, header)
+	o.writeBody(&b, header.Get("Content-Type"), cw.body.Bytes())
+
+	if o.out != nil {
+		_, _ = io.WriteString(o.out, b.String())
+		return
+	}
+	log.Print(b.String())
+}
+
+func (o *dumpOptions) writeHeaders(b *strings.Builder, h http.Header) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range h[k] {
+			if o.redact[http.CanonicalHeaderKey(k)] {
+				v = "[REDACTED]"
+			}
+			fmt.Fprintf(b, "%s: %s\n", k, v)
+		}
+	}
+}
+
+func (o *dumpOptions) writeBody(b *strings.Builder, contentType string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	b.WriteString("\n")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasPrefix(mediaType, "multipart/"):
+		fmt.Fprintf(b, "(multipart body, %d bytes)\n", len(body))
+		return
+	case mediaType == "application/x-www-form-urlencoded":
+		body = o.redactForm(body)
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		body = o.redactJSON(body)
+	}
+	if o.maxBody > 0 && len(body) > o.maxBody {
+		b.Write(body[:o.maxBody])
+		fmt.Fprintf(b, "\n... (%d more bytes)\n", len(body)-o.maxBody)
+		return
+	}
+	b.Write(body)
+	if body[len(body)-1] != '\n' {
+		b.WriteString("\n")
+	}
+}
+
+// redactForm masks the redacted fields of a urlencoded body.
+func (o *dumpOptions) redactForm(body []byte) []byte {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return body
+	}
+	redacted := false
+	for k := range values {
+		if o.fields[strings.ToLower(k)] {
+			redacted = true
+		}
+	}
+	if !redacted {
+		return body
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, k := range keys {
+		for _, v := range values[k] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(k) + "=")
+			if o.fields[strings.ToLower(k)] {
+				b.WriteString("[REDACTED]")
+			} else {
+				b.WriteString(url.QueryEscape(v))
+			}
+		}
+	}
+	return []byte(b.String())
+}
+
+// redactJSON masks the redacted fields of a JSON body at any depth. Bodies without
+// such fields, or that do not parse, are returned unchanged.
+func (o *dumpOptions) redactJSON(body []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+	if !o.redactValue(v) {
+		return body
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return out
+}
+
+func (o *dumpOptions) redactValue(v any) bool {
+	redacted := false
+	switch x := v.(type) {
+	case map[string]any:
+		for k, val := range x {
+			if o.fields[strings.ToLower(k)] {
+				x[k] = "[REDACTED]"
+				redacted = true
+				continue
+			}
+			if o.redactValue(val) {
+				redacted = true
+			}
+		}
+	case []any:
+		for _, val := range x {
+			if o.redactValue(val) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
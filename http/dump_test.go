@@ -0,0 +1,78 @@
+package http
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func dumpRouter(debug bool, out *bytes.Buffer) *Router {
+	r := NewRouter()
+	r.SetDebug(debug)
+	r.Use(DumpHTTP(DumpTo(out)))
+	r.Post("/login", func(c *Context) {
+		c.JSON(http.StatusOK, map[string]any{"user": "ada", "token": "tok-123"})
+	})
+	return r
+}
+
+func TestDumpHTTPOnlyInDebug(t *testing.T) {
+	var out bytes.Buffer
+	r := dumpRouter(false, &out)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("{}")))
+	if out.Len() != 0 {
+		t.Fatalf("dumped outside debug mode:\n%s", out.String())
+	}
+
+	r.SetDebug(true)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("{}")))
+	if !strings.Contains(out.String(), "--> POST /login") {
+		t.Fatalf("missing dump in debug mode:\n%s", out.String())
+	}
+}
+
+func TestDumpHTTPRedactsJSONFields(t *testing.T) {
+	var out bytes.Buffer
+	r := dumpRouter(true, &out)
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email":"ada@example.com","auth":{"Password":"hunter2"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer abc")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	dump := out.String()
+	for _, secret := range []string{"hunter2", "tok-123", "Bearer abc"} {
+		if strings.Contains(dump, secret) {
+			t.Errorf("dump leaks %q:\n%s", secret, dump)
+		}
+	}
+	if !strings.Contains(dump, "ada@example.com") {
+		t.Errorf("dump lost a non-sensitive field:\n%s", dump)
+	}
+}
+
+func TestDumpHTTPRedactsFormFields(t *testing.T) {
+	var out bytes.Buffer
+	r := dumpRouter(true, &out)
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("email=ada%40example.com&password=hunter2"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if dump := out.String(); strings.Contains(dump, "hunter2") || !strings.Contains(dump, "password=[REDACTED]") {
+		t.Fatalf("form password not redacted:\n%s", dump)
+	}
+}
+
+func TestDumpHTTPRedactsCSRFToken(t *testing.T) {
+	var out bytes.Buffer
+	r := dumpRouter(true, &out)
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("_token=csrf-secret-value&email=ada%40example.com"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	dump := out.String()
+	if strings.Contains(dump, "csrf-secret-value") || !strings.Contains(dump, "_token=[REDACTED]") {
+		t.Fatalf("CSRF token not redacted:\n%s", dump)
+	}
+}
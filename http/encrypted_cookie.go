@@ -0,0 +1,101 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCookie is returned by EncryptedCookie for cookies that were not set by
+// SetEncryptedCookie under that name with the current key.
+var ErrInvalidCookie = errors.New("http: invalid encrypted cookie")
+
+// maxCookieSize is the cookie size browsers are guaranteed to keep.
+const maxCookieSize = 4096
+
+// SetEncryptedCookie stores value, JSON-encoded and encrypted with a key derived
+// from APP_KEY, in a cookie: small client-side state such as preferences that the
+// client must not read or forge. Read it back with EncryptedCookie.
+//
+// maxAge 0 makes a browser-session cookie and a negative maxAge deletes it. The
+// cookie is HttpOnly; Path, Domain, Secure and SameSite follow the Sessions
+// middleware's SessionManager when it runs, and otherwise default to "/", the
+// request host, HTTPS-only on HTTPS requests and Lax.
+//
+// It panics with an HTTPError (500) when no key is configured, value cannot be
+// encoded or the cookie would exceed 4KB.
+func (c *Context) SetEncryptedCookie(name string, value any, maxAge time.Duration) {
+	cookie := c.cookieDefaults(name)
+	switch {
+	case maxAge < 0:
+		cookie.MaxAge = -1
+	case maxAge > 0:
+		cookie.MaxAge = int(maxAge / time.Second)
+		cookie.Expires = time.Now().Add(maxAge)
+	}
+
+	if maxAge >= 0 {
+		payload, err := json.Marshal(value)
+		if err != nil {
+			panic(HTTPError{Status: http.StatusInternalServerError, Message: "Failed to encode cookie", Err: err})
+		}
+		// The name is sealed with the value so a cookie cannot be replayed under another name.
+		sealed, err := seal(purposeKey(c.appKey(), purposeCookie), append([]byte(name+"\x00"), payload...))
+		if err != nil {
+			panic(HTTPError{Status: http.StatusInternalServerError, Message: "Failed to encrypt cookie", Err: err})
+		}
+		cookie.Value = sealed
+	}
+
+	if v := cookie.String(); len(v) > maxCookieSize {
+		panic(HTTPError{Status: http.StatusInternalServerError, Message: "Encrypted cookie too large", Err: fmt.Errorf("cookie %q is %d bytes", name, len(v))})
+	}
+	http.SetCookie(c.ResponseWriter, cookie)
+}
+
+// EncryptedCookie decrypts the cookie set by SetEncryptedCookie into v. It returns
+// http.ErrNoCookie when the cookie is missing and ErrInvalidCookie when it was
+// tampered with, set under another name or encrypted with another key.
+//
+// It panics with an HTTPError (500) when no key is configured.
+func (c *Context) EncryptedCookie(name string, v any) error {
+	ck, err := c.Request.Cookie(name)
+	if err != nil {
+		return err
+	}
+	plain, err := unseal(purposeKey(c.appKey(), purposeCookie), ck.Value)
+	if err != nil {
+		return ErrInvalidCookie
+	}
+	payload, ok := strings.CutPrefix(string(plain), name+"\x00")
+	if !ok {
+		return ErrInvalidCookie
+	}
+	if err := json.Unmarshal([]byte(payload), v); err != nil {
+		return fmt.Errorf("http: decode cookie %q: %w", name, err)
+	}
+	return nil
+}
+
+func (c *Context) cookieDefaults(name string) *http.Cookie {
+	if sm := c.sessions; sm != nil {
+		return &http.Cookie{
+			Name:     name,
+			Path:     sm.Path,
+			Domain:   sm.Domain,
+			Secure:   sm.Secure,
+			HttpOnly: true,
+			SameSite: sm.SameSite,
+		}
+	}
+	return &http.Cookie{
+		Name:     name,
+		Path:     "/",
+		Secure:   c.Request.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrInvalidParam is returned by DecryptParam for tokens that were not produced by
+// EncryptParam with the current key, including tampered or truncated ones.
+var ErrInvalidParam = errors.New("http: invalid encrypted parameter")
+
+// SetParamKey sets the application key used by Context.EncryptParam and
+// DecryptParam (and Context.SetEncryptedCookie). It accepts the same formats as
+// APP_KEY, including "base64:...". Each use derives its own key from it.
+//
+// The kernel sets it from APP_KEY.
+func (r *Router) SetParamKey(appKey string) error {
+	key, err := deriveKey(appKey)
+	if err != nil {
+		return err
+	}
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.appKey = key
+	return nil
+}
+
+// EncryptParam encrypts value into an opaque token for embedding in URLs, such as
+// an unsubscribe link carrying the user's email. Unlike a signed URL the value is
+// hidden from the client, and DecryptParam gives it back.
+//
+// Tokens use AES-GCM with a key derived for this purpose only and unpadded
+// base64url, so they are safe in paths and query strings without escaping. They
+// are roughly 4/3 × (len(value) + 28) characters long: a 12-byte nonce and a
+// 16-byte tag are added. Tokens do not expire; put a timestamp in the value if
+// that matters.
+//
+// It panics with an HTTPError (500) when no key is configured.
+func (c *Context) EncryptParam(value string) string {
+	token, err := seal(purposeKey(c.appKey(), purposeParams), []byte(value))
+	if err != nil {
+		panic(HTTPError{Status: http.StatusInternalServerError, Message: "Failed to encrypt parameter", Err: err})
+	}
+	return token
+}
+
+// DecryptParam returns the value encrypted by EncryptParam, or ErrInvalidParam.
+//
+// It panics with an HTTPError (500) when no key is configured.
+func (c *Context) DecryptParam(token string) (string, error) {
+	plain, err := unseal(purposeKey(c.appKey(), purposeParams), token)
+	if err != nil {
+		return "", ErrInvalidParam
+	}
+	return string(plain), nil
+}
+
+// appKey returns the router's application key (see SetParamKey).
+func (c *Context) appKey() []byte {
+	var key []byte
+	if c.router != nil {
+		c.router.state.mu.RLock()
+		key = c.router.state.appKey
+		c.router.state.mu.RUnlock()
+	}
+	if key == nil {
+		panic(HTTPError{Status: http.StatusInternalServerError, Message: "Encryption key is not configured"})
+	}
+	return key
+}
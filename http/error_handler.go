@@ -0,0 +1,96 @@
+package http
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/jimo-go/framework/validation"
+)
+
+// FieldErrorer is implemented by errors carrying per-field messages, such as
+// validation.Error. The default error handler returns them as "fields".
+type FieldErrorer interface {
+	FieldErrors() map[string]string
+}
+
+// SetErrorHandler replaces how recovered panics are turned into responses.
+//
+// fn receives the recovered value: an HTTPError (or *HTTPError) raised by the
+// framework or the application, or anything else for unexpected panics. It runs
+// before Router.OnError reporting and is not called when the client is gone.
+// A nil fn restores DefaultErrorHandler.
+//
+//	r.SetErrorHandler(func(ctx *jimohttp.Context, rec any) {
+//		if e, ok := rec.(jimohttp.HTTPError); ok && e.Status < 500 {
+//			jimohttp.DefaultErrorHandler(ctx, rec)
+//			return
+//		}
+//		ctx.String(http.StatusInternalServerError, "Something went wrong.")
+//	})
+func (r *Router) SetErrorHandler(fn func(ctx *Context, rec any)) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.errorHandler = fn
+}
+
+// DefaultErrorHandler writes the JSON error response used when no error handler
+// is set: {"message": ...}, plus "fields" for FieldErrorer errors, wrapped by
+// the WrapJSON envelope after WrapJSONErrors(true). Panics that are not
+// HTTPErrors become a 500 without details.
+func DefaultErrorHandler(ctx *Context, rec any) {
+	var wrap func(int, any) any
+	if ctx.router != nil {
+		wrap = ctx.router.jsonWrapper(true)
+	}
+	switch v := rec.(type) {
+	case HTTPError:
+		writeJSONError(ctx.ResponseWriter, v.Status, v.Message, v.Err, wrap)
+	case *HTTPError:
+		writeJSONError(ctx.ResponseWriter, v.Status, v.Message, v.Err, wrap)
+	default:
+		writeJSONError(ctx.ResponseWriter, http.StatusInternalServerError, "Internal Server Error", nil, wrap)
+	}
+}
+
+// handleError renders rec with the configured error handler. A panic inside a
+// custom handler falls back to the default response.
+func (r *Router) handleError(ctx *Context, rec any) {
+	r.state.mu.RLock()
+	fn := r.state.errorHandler
+	r.state.mu.RUnlock()
+	if fn == nil {
+		DefaultErrorHandler(ctx, rec)
+		return
+	}
+
+	defer func() {
+		if again := recover(); again != nil {
+			log.Printf("http: error handler panicked: %v", again)
+			DefaultErrorHandler(ctx, rec)
+		}
+	}()
+	fn(ctx, rec)
+}
+
+// JSONError writes an error response without panicking, in the same form as a
+// panicked HTTPError{Status: status, Message: message}: it goes through the
+// router's error handler (see SetErrorHandler), so returned and thrown errors
+// look alike, JSON by default. Unlike a panic it is not reported to OnError, and
+// the handler keeps running afterwards.
+func (c *Context) JSONError(status int, message string) {
+	c.writeError(HTTPError{Status: status, Message: message})
+}
+
+// JSONErrors is JSONError for per-field errors, answered like a failed
+// MustValidate: {"message": "Validation failed", "fields": {...}} by default.
+func (c *Context) JSONErrors(status int, fields map[string]string) {
+	c.writeError(HTTPError{Status: status, Message: "Validation failed", Err: validation.Error{Fields: fields}})
+}
+
+func (c *Context) writeError(e HTTPError) {
+	if c.router == nil {
+		DefaultErrorHandler(c, e)
+		return
+	}
+	c.router.handleError(c, e)
+}
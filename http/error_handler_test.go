@@ -0,0 +1,50 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestJSONError(t *testing.T) {
+	r := NewRouter()
+	r.Get("/", func(c *Context) { c.JSONError(http.StatusConflict, "Already taken") })
+
+	rec := serve(r, http.MethodGet, "/")
+	if rec.Code != http.StatusConflict || rec.Body.String() != `{"message":"Already taken"}`+"\n" {
+		t.Fatalf("got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	r := NewRouter()
+	r.Post("/", func(c *Context) {
+		c.JSONErrors(http.StatusUnprocessableEntity, map[string]string{"email": "is taken"})
+	})
+
+	rec := serve(r, http.MethodPost, "/")
+	want := `{"fields":{"email":"is taken"},"message":"Validation failed"}` + "\n"
+	if rec.Code != http.StatusUnprocessableEntity || rec.Body.String() != want {
+		t.Fatalf("got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestJSONErrorUsesErrorHandler(t *testing.T) {
+	r := NewRouter()
+	r.SetErrorHandler(func(ctx *Context, rec any) {
+		e := rec.(HTTPError)
+		ctx.String(e.Status, "custom: "+e.Message)
+	})
+	ran := false
+	r.Get("/", func(c *Context) {
+		c.JSONError(http.StatusForbidden, "Nope")
+		ran = true
+	})
+
+	rec := serve(r, http.MethodGet, "/")
+	if rec.Code != http.StatusForbidden || rec.Body.String() != "custom: Nope" {
+		t.Fatalf("got %d %q", rec.Code, rec.Body.String())
+	}
+	if !ran {
+		t.Error("handler stopped after JSONError")
+	}
+}
@@ -0,0 +1,99 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// PanicError is a recovered panic together with the stack of the goroutine that
+// panicked.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error (including an HTTPError).
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// ErrorReportOption configures Router.OnError.
+type ErrorReportOption func(*errorReporter)
+
+// ReportServerErrors also reports HTTPError panics with a 5xx status. By default
+// only panics that are not HTTPErrors (bugs) are reported.
+func ReportServerErrors() ErrorReportOption {
+	return func(e *errorReporter) { e.serverErrors = true }
+}
+
+type errorReporter struct {
+	fn           func(err error, ctx *Context)
+	serverErrors bool
+}
+
+// OnError sets a hook for shipping failures to an error tracker such as Sentry.
+//
+// It is called after the error response was written, with a *PanicError carrying
+// the recovered value and its stack, for panics in handlers and middleware that are
+// not HTTPErrors, and for panics in functions registered with Context.Defer. ctx is
+// the request's context; the response is committed by then. A nil fn removes the hook.
+func (r *Router) OnError(fn func(err error, ctx *Context), opts ...ErrorReportOption) {
+	var rep *errorReporter
+	if fn != nil {
+		rep = &errorReporter{fn: fn}
+		for _, opt := range opts {
+			if opt != nil {
+				opt(rep)
+			}
+		}
+	}
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.onError = rep
+}
+
+func (r *Router) errorReporter() *errorReporter {
+	if r == nil {
+		return nil
+	}
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+	return r.state.onError
+}
+
+// reportPanic passes a value recovered while serving ctx to the OnError hook, if the
+// hook wants it. It must be called from the deferred recover so the stack is intact.
+func (r *Router) reportPanic(ctx *Context, rec any) {
+	rep := r.errorReporter()
+	if rep == nil {
+		return
+	}
+	switch v := rec.(type) {
+	case HTTPError:
+		if !rep.serverErrors || v.Status < http.StatusInternalServerError {
+			return
+		}
+	case *HTTPError:
+		if !rep.serverErrors || v.Status < http.StatusInternalServerError {
+			return
+		}
+	}
+	rep.report(&PanicError{Value: rec, Stack: debug.Stack()}, ctx)
+}
+
+func (e *errorReporter) report(err error, ctx *Context) {
+	// A failing hook must not replace the original error.
+	defer func() {
+		if rec := recover(); rec != nil {
+			log.Printf("http: error hook panicked: %v (reporting %v)", rec, err)
+		}
+	}()
+	e.fn(err, ctx)
+}
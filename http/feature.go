@@ -0,0 +1,21 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/jimo-go/framework/features"
+)
+
+// RequireFeature responds 404 unless the named feature flag is enabled.
+//
+// Flags are evaluated per request, so runtime overrides apply immediately.
+func RequireFeature(name string) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) {
+			if !features.IsEnabled(name) {
+				panic(HTTPError{Status: http.StatusNotFound, Message: "Not Found"})
+			}
+			next(ctx)
+		}
+	}
+}
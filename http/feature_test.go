@@ -0,0 +1,25 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jimo-go/framework/features"
+)
+
+func TestRequireFeature(t *testing.T) {
+	const name = "beta-dashboard"
+	t.Cleanup(func() { features.Reset(name) })
+
+	r := NewRouter()
+	r.Get("/beta", func(c *Context) { c.String(http.StatusOK, "beta") }, WithMiddleware(RequireFeature(name)))
+
+	features.Disable(name)
+	if rec := serve(r, http.MethodGet, "/beta"); rec.Code != http.StatusNotFound {
+		t.Fatalf("flag off: status = %d, want 404", rec.Code)
+	}
+	features.Enable(name)
+	if rec := serve(r, http.MethodGet, "/beta"); rec.Code != http.StatusOK {
+		t.Fatalf("flag on: status = %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,58 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// TrustedHosts rejects requests whose Host header is not in the allowlist.
+//
+// Entries are matched case-insensitively and without the port. A leading "*."
+// matches any subdomain (but not the bare domain itself). Requests with an unknown
+// host fail with 400. With an empty allowlist the middleware is a no-op.
+func TrustedHosts(hosts ...string) Middleware {
+	allowed := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		h = strings.ToLower(strings.TrimSpace(h))
+		if h != "" {
+			allowed = append(allowed, h)
+		}
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) {
+			if len(allowed) == 0 || hostAllowed(requestHost(ctx.Request), allowed) {
+				next(ctx)
+				return
+			}
+			panic(HTTPError{Status: http.StatusBadRequest, Message: "Invalid host"})
+		}
+	}
+}
+
+func requestHost(r *http.Request) string {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+func hostAllowed(host string, allowed []string) bool {
+	if host == "" {
+		return false
+	}
+	for _, pattern := range allowed {
+		if suffix, ok := strings.CutPrefix(pattern, "*"); ok {
+			if strings.HasSuffix(host, suffix) && len(host) > len(suffix) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrustedHosts(t *testing.T) {
+	r := NewRouter()
+	r.Use(TrustedHosts("example.com", "*.example.org"))
+	r.Get("/reset", func(c *Context) { c.String(http.StatusOK, "ok") })
+
+	for host, want := range map[string]int{
+		"example.com":          http.StatusOK,
+		"EXAMPLE.com:8080":     http.StatusOK,
+		"example.com.":         http.StatusOK,
+		"api.example.org":      http.StatusOK,
+		"a.b.example.org":      http.StatusOK,
+		"example.org":          http.StatusBadRequest,
+		"evil.com":             http.StatusBadRequest,
+		"example.com.evil.com": http.StatusBadRequest,
+		"evilexample.org":      http.StatusBadRequest,
+		"":                     http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/reset", nil)
+		req.Host = host
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("Host %q = %d, want %d", host, rec.Code, want)
+		}
+	}
+}
+
+func TestTrustedHostsEmptyListAllowsAll(t *testing.T) {
+	r := NewRouter()
+	r.Use(TrustedHosts())
+	r.Get("/", func(c *Context) { c.String(http.StatusOK, "ok") })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Host = "anything.test"
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+}
@@ -0,0 +1,199 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// StoredResponse is a response saved for replay by Idempotency.
+type StoredResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// IdempotencyStore keeps responses for Idempotency.
+//
+// Reserve must be atomic: it returns the stored response when the key has completed,
+// otherwise it reserves the key and reports whether the reservation succeeded (false
+// means another request with the same key is in flight).
+type IdempotencyStore interface {
+	Reserve(key string, ttl time.Duration) (resp *StoredResponse, reserved bool)
+	Complete(key string, resp *StoredResponse, ttl time.Duration)
+	Release(key string)
+}
+
+// MemoryIdempotencyStore is an in-process IdempotencyStore.
+//
+// It is not shared between instances; use a shared store behind a load balancer.
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]idempotencyEntry
+}
+
+type idempotencyEntry struct {
+	resp    *StoredResponse // nil while in flight
+	expires time.Time
+}
+
+// NewMemoryIdempotencyStore creates an empty in-memory store.
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{entries: make(map[string]idempotencyEntry)}
+}
+
+func (s *MemoryIdempotencyStore) Reserve(key string, ttl time.Duration) (*StoredResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if e, ok := s.entries[key]; ok && now.Before(e.expires) {
+		return e.resp, false
+	}
+	s.entries[key] = idempotencyEntry{expires: now.Add(ttl)}
+	return nil, true
+}
+
+func (s *MemoryIdempotencyStore) Complete(key string, resp *StoredResponse, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.entries[key] = idempotencyEntry{resp: resp, expires: now.Add(ttl)}
+	// Opportunistically drop expired entries.
+	for k, e := range s.entries {
+		if now.After(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+}
+
+func (s *MemoryIdempotencyStore) Release(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok && e.resp == nil {
+		delete(s.entries, key)
+	}
+}
+
+type idempotencyOptions struct {
+	store IdempotencyStore
+	ttl   time.Duration
+	scope func(*Context) string
+}
+
+// IdempotencyOption configures Idempotency.
+type IdempotencyOption func(*idempotencyOptions)
+
+// IdempotencyTTL sets how long responses are replayed (default 24h).
+func IdempotencyTTL(d time.Duration) IdempotencyOption {
+	return func(o *idempotencyOptions) { o.ttl = d }
+}
+
+// WithIdempotencyStore sets the response store (default: a new MemoryIdempotencyStore).
+func WithIdempotencyStore(s IdempotencyStore) IdempotencyOption {
+	return func(o *idempotencyOptions) { o.store = s }
+}
+
+// IdempotencyBy sets who a key belongs to (default: IdempotencyCaller), e.g. the
+// authenticated user's id. Requests share stored responses only when scope
+// returns the same value for them.
+func IdempotencyBy(scope func(*Context) string) IdempotencyOption {
+	return func(o *idempotencyOptions) { o.scope = scope }
+}
+
+// IdempotencyCaller is the default scope of Idempotency keys: the Authorization
+// header, else the session (see Context.SessionID), else the client IP. The
+// header is hashed, so credentials never end up in the store.
+func IdempotencyCaller(ctx *Context) string {
+	if auth := ctx.Request.Header.Get("Authorization"); auth != "" {
+		sum := sha256.Sum256([]byte(auth))
+		return "auth:" + hex.EncodeToString(sum[:])
+	}
+	if id := ctx.SessionID(); id != "" {
+		return "session:" + id
+	}
+	return "ip:" + remoteIP(ctx)
+}
+
+// Idempotency makes unsafe requests carrying an Idempotency-Key header safe to retry.
+//
+// The first response for a key is stored and replayed for duplicates within the
+// TTL, with an Idempotent-Replayed: true header. A duplicate arriving while the
+// first is still running fails with 409. Server errors (5xx) and panics are not stored, so the client can retry them.
+// Requests without the header pass through.
+//
+// Keys are scoped to the method, the path and the caller (see IdempotencyBy), so
+// a client reusing or guessing another client's key never gets its response.
+func Idempotency(opts ...IdempotencyOption) Middleware {
+	o := idempotencyOptions{ttl: 24 * time.Hour}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	if o.store == nil {
+		o.store = NewMemoryIdempotencyStore()
+	}
+	if o.scope == nil {
+		o.scope = IdempotencyCaller
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) {
+			idem := ctx.Request.Header.Get("Idempotency-Key")
+			switch ctx.Request.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+				idem = ""
+			}
+			if idem == "" {
+				next(ctx)
+				return
+			}
+
+			key := ctx.Request.Method + " " + ctx.Request.URL.Path + " " + o.scope(ctx) + " " + idem
+			stored, reserved := o.store.Reserve(key, o.ttl)
+			if stored != nil {
+				replay(ctx.ResponseWriter, stored)
+				return
+			}
+			if !reserved {
+				panic(HTTPError{Status: http.StatusConflict, Message: "A request with this Idempotency-Key is already in progress"})
+			}
+
+			cw := &captureWriter{ResponseWriter: ctx.ResponseWriter}
+			ctx.ResponseWriter = cw
+			completed := false
+			defer func() {
+				ctx.ResponseWriter = cw.ResponseWriter
+				if !completed || cw.status >= http.StatusInternalServerError {
+					o.store.Release(key)
+					return
+				}
+				status := cw.status
+				if status == 0 {
+					status = http.StatusOK
+				}
+				o.store.Complete(key, &StoredResponse{Status: status, Header: cw.header, Body: cw.body.Bytes()}, o.ttl)
+			}()
+
+			next(ctx)
+			completed = true
+		}
+	}
+}
+
+func replay(w http.ResponseWriter, resp *StoredResponse) {
+	h := w.Header()
+	for k, vals := range resp.Header {
+		if k == "Set-Cookie" {
+			continue
+		}
+		h[k] = append([]string(nil), vals...)
+	}
+	h.Set("Idempotent-Replayed", "true")
+	w.WriteHeader(resp.Status)
+	_, _ = w.Write(resp.Body)
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func idempotencyRouter(opts ...IdempotencyOption) (*Router, *int) {
+	calls := 0
+	r := NewRouter()
+	r.Use(Idempotency(opts...))
+	r.Post("/orders", func(c *Context) {
+		calls++
+		c.String(http.StatusCreated, fmt.Sprintf("order %d", calls))
+	})
+	return r, &calls
+}
+
+func postOrder(r *Router, key string, setup func(*http.Request)) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+	req.Header.Set("Idempotency-Key", key)
+	if setup != nil {
+		setup(req)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyReplaysForSameCaller(t *testing.T) {
+	r, calls := idempotencyRouter()
+	auth := func(req *http.Request) { req.Header.Set("Authorization", "Bearer alice") }
+
+	first := postOrder(r, "k1", auth)
+	second := postOrder(r, "k1", auth)
+	if *calls != 1 || second.Body.String() != first.Body.String() || second.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("calls=%d first=%q second=%q", *calls, first.Body.String(), second.Body.String())
+	}
+}
+
+func TestIdempotencyKeysAreScopedToCaller(t *testing.T) {
+	r, calls := idempotencyRouter()
+
+	postOrder(r, "k1", func(req *http.Request) { req.Header.Set("Authorization", "Bearer alice") })
+	rec := postOrder(r, "k1", func(req *http.Request) { req.Header.Set("Authorization", "Bearer mallory") })
+	if *calls != 2 || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("another caller got the stored response: calls=%d body=%q", *calls, rec.Body.String())
+	}
+
+	rec = postOrder(r, "k1", func(req *http.Request) { req.RemoteAddr = "203.0.113.9:1234" })
+	if *calls != 3 || rec.Header().Get("Idempotent-Replayed") != "" {
+		t.Fatalf("anonymous caller got the stored response: calls=%d body=%q", *calls, rec.Body.String())
+	}
+}
+
+func TestIdempotencyBy(t *testing.T) {
+	r, calls := idempotencyRouter(IdempotencyBy(func(c *Context) string { return c.Request.Header.Get("X-Tenant") }))
+	tenant := func(name string) func(*http.Request) {
+		return func(req *http.Request) { req.Header.Set("X-Tenant", name) }
+	}
+
+	postOrder(r, "k1", tenant("acme"))
+	if rec := postOrder(r, "k1", tenant("acme")); rec.Header().Get("Idempotent-Replayed") != "true" {
+		t.Fatalf("same tenant was not replayed: %q", rec.Body.String())
+	}
+	postOrder(r, "k1", tenant("globex"))
+	if *calls != 2 {
+		t.Fatalf("calls = %d, want 2", *calls)
+	}
+}
@@ -0,0 +1,122 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// input holds the request body values parsed once for Context.Input.
+type input struct {
+	json map[string]any
+	form url.Values
+}
+
+// Input returns a request value by key, wherever the client sent it. Sources are
+// checked in this order:
+//
+//  1. the JSON body (application/json); nested fields are reached with dots, as
+//     in "user.email", and non-string values are returned in their JSON form
+//  2. the form body (application/x-www-form-urlencoded, or multipart/form-data
+//     once parsed by the ParseMultipart middleware)
+//  3. the query string
+//
+// The body is buffered and put back, so MustBind and friends still work after
+// Input. A malformed body is treated as empty; JSON null counts as absent.
+func (c *Context) Input(key string) (string, bool) {
+	in := c.parseInput()
+	if v, ok := lookupJSONPath(in.json, key); ok {
+		return v, true
+	}
+	if vals, ok := in.form[key]; ok && len(vals) > 0 {
+		return vals[0], true
+	}
+	if mf := c.Request.MultipartForm; mf != nil {
+		if vals, ok := mf.Value[key]; ok && len(vals) > 0 {
+			return vals[0], true
+		}
+	}
+	if vals, ok := c.Request.URL.Query()[key]; ok && len(vals) > 0 {
+		return vals[0], true
+	}
+	return "", false
+}
+
+// InputDefault is like Input but returns def when the key is absent.
+func (c *Context) InputDefault(key, def string) string {
+	if v, ok := c.Input(key); ok {
+		return v
+	}
+	return def
+}
+
+func (c *Context) parseInput() *input {
+	if c.input != nil {
+		return c.input
+	}
+	c.input = &input{}
+
+	req := c.Request
+	mt, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	isJSON := isJSONContentType(req.Header.Get("Content-Type"))
+	if req.Body == nil || (!isJSON && mt != "application/x-www-form-urlencoded") {
+		return c.input
+	}
+
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return c.input
+	}
+
+	if isJSON {
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		_ = dec.Decode(&c.input.json)
+		return c.input
+	}
+	c.input.form, _ = url.ParseQuery(string(body))
+	return c.input
+}
+
+// lookupJSONPath resolves a dotted key in decoded JSON. Exact keys containing dots
+// take precedence over nesting.
+func lookupJSONPath(m map[string]any, key string) (string, bool) {
+	if m == nil {
+		return "", false
+	}
+	v, ok := m[key]
+	if !ok {
+		head, rest, nested := strings.Cut(key, ".")
+		if !nested {
+			return "", false
+		}
+		child, isMap := m[head].(map[string]any)
+		if !isMap {
+			return "", false
+		}
+		return lookupJSONPath(child, rest)
+	}
+
+	switch x := v.(type) {
+	case nil:
+		return "", false
+	case string:
+		return x, true
+	case json.Number:
+		return x.String(), true
+	case bool:
+		return strconv.FormatBool(x), true
+	default:
+		b, err := json.Marshal(x)
+		if err != nil {
+			return "", false
+		}
+		return string(b), true
+	}
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+type requireJSONOptions struct {
+	allowEmpty bool
+}
+
+// RequireJSONOption configures RequireJSON.
+type RequireJSONOption func(*requireJSONOptions)
+
+// AllowEmptyBody lets unsafe requests without a body (e.g. DELETE) through without a Content-Type.
+func AllowEmptyBody() RequireJSONOption {
+	return func(o *requireJSONOptions) { o.allowEmpty = true }
+}
+
+// RequireJSON rejects unsafe requests (POST, PUT, PATCH, DELETE) whose Content-Type
+// is not JSON with 415. application/json and application/*+json are accepted.
+//
+// Safe methods (GET, HEAD, OPTIONS) always pass. It is meant for API groups, whose
+// JSON requests CSRF already skips.
+func RequireJSON(opts ...RequireJSONOption) Middleware {
+	var o requireJSONOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) {
+			r := ctx.Request
+			switch r.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+				next(ctx)
+				return
+			}
+			if o.allowEmpty && r.ContentLength == 0 && len(r.TransferEncoding) == 0 {
+				next(ctx)
+				return
+			}
+			if !isJSONContentType(r.Header.Get("Content-Type")) {
+				panic(HTTPError{Status: http.StatusUnsupportedMediaType, Message: "Content-Type must be application/json"})
+			}
+			next(ctx)
+		}
+	}
+}
+
+func isJSONContentType(v string) bool {
+	mt, _, err := mime.ParseMediaType(v)
+	if err != nil {
+		return false
+	}
+	return mt == "application/json" || (strings.HasPrefix(mt, "application/") && strings.HasSuffix(mt, "+json"))
+}
@@ -0,0 +1,51 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequireJSON(t *testing.T) {
+	newRouter := func(opts ...RequireJSONOption) *Router {
+		r := NewRouter()
+		r.Use(RequireJSON(opts...))
+		h := func(c *Context) { c.String(http.StatusOK, "ok") }
+		r.Get("/items", h)
+		r.Post("/items", h)
+		r.Delete("/items", h)
+		return r
+	}
+
+	for _, tc := range []struct {
+		method, contentType, body string
+		allowEmpty                bool
+		want                      int
+	}{
+		{http.MethodGet, "", "", false, http.StatusOK},
+		{http.MethodPost, "application/json", "{}", false, http.StatusOK},
+		{http.MethodPost, "application/json; charset=utf-8", "{}", false, http.StatusOK},
+		{http.MethodPost, "application/vnd.api+json", "{}", false, http.StatusOK},
+		{http.MethodPost, "text/plain", "{}", false, http.StatusUnsupportedMediaType},
+		{http.MethodPost, "application/x-www-form-urlencoded", "a=1", false, http.StatusUnsupportedMediaType},
+		{http.MethodPost, "", "{}", false, http.StatusUnsupportedMediaType},
+		{http.MethodDelete, "", "", false, http.StatusUnsupportedMediaType},
+		{http.MethodDelete, "", "", true, http.StatusOK},
+		{http.MethodPost, "", "{}", true, http.StatusUnsupportedMediaType},
+	} {
+		var opts []RequireJSONOption
+		if tc.allowEmpty {
+			opts = append(opts, AllowEmptyBody())
+		}
+		req := httptest.NewRequest(tc.method, "/items", strings.NewReader(tc.body))
+		if tc.contentType != "" {
+			req.Header.Set("Content-Type", tc.contentType)
+		}
+		rec := httptest.NewRecorder()
+		newRouter(opts...).ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s %q (allowEmpty %v) = %d, want %d", tc.method, tc.contentType, tc.allowEmpty, rec.Code, tc.want)
+		}
+	}
+}
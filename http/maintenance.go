@@ -0,0 +1,132 @@
+package http
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaintenanceMode describes how the application responds while down for maintenance.
+//
+// It is stored as JSON in the maintenance sentinel file.
+type MaintenanceMode struct {
+	// Message is returned in the 503 response body.
+	Message string `json:"message,omitempty"`
+	// RetryAfter is sent as the Retry-After header, in seconds (0 omits the header).
+	RetryAfter int `json:"retry_after,omitempty"`
+	// Secret lets requests bypass maintenance mode via the X-Maintenance-Secret header
+	// or the jimo_maintenance cookie. Visiting /<secret> sets that cookie and
+	// redirects to /, so browsers can use the site while it is down.
+	Secret string `json:"secret,omitempty"`
+	// Allow lists paths (and their sub-paths) that stay reachable, e.g. "/health".
+	Allow []string `json:"allow,omitempty"`
+}
+
+// maintenanceCookie carries the secret of browsers that bypass maintenance mode.
+const maintenanceCookie = "jimo_maintenance"
+
+// maintenanceRecheck is how long Maintenance trusts its last look at the sentinel file.
+var maintenanceRecheck = time.Second
+
+// Maintenance returns 503 for all requests while the sentinel file at path exists.
+//
+// The file is checked at most once a second, so `jimo down` and `jimo up` take
+// effect without a restart and without a stat call on every request. Its contents
+// are re-read only when the modification time changes.
+func Maintenance(path string) Middleware {
+	var (
+		mu      sync.Mutex
+		checked time.Time
+		down    bool
+		modTime time.Time
+		mode    MaintenanceMode
+	)
+
+	current := func() (MaintenanceMode, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		now := time.Now()
+		if !checked.IsZero() && now.Sub(checked) < maintenanceRecheck {
+			return mode, down
+		}
+		checked = now
+
+		info, err := os.Stat(path)
+		if err != nil {
+			down, modTime, mode = false, time.Time{}, MaintenanceMode{}
+			return mode, false
+		}
+		down = true
+		if !info.ModTime().Equal(modTime) {
+			mode = MaintenanceMode{}
+			if b, err := os.ReadFile(path); err == nil && len(b) > 0 {
+				_ = json.Unmarshal(b, &mode)
+			}
+			modTime = info.ModTime()
+		}
+		return mode, true
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) {
+			m, down := current()
+			if !down || m.bypass(ctx.Request) {
+				next(ctx)
+				return
+			}
+			if m.Secret != "" && cleanPath(ctx.Request.URL.Path) == "/"+m.Secret {
+				http.SetCookie(ctx.ResponseWriter, &http.Cookie{
+					Name:     maintenanceCookie,
+					Value:    m.Secret,
+					Path:     "/",
+					HttpOnly: true,
+					Secure:   ctx.Request.TLS != nil,
+					SameSite: http.SameSiteLaxMode,
+				})
+				ctx.ResponseWriter.Header().Set("Location", "/")
+				ctx.ResponseWriter.WriteHeader(http.StatusFound)
+				return
+			}
+
+			if m.RetryAfter > 0 {
+				ctx.ResponseWriter.Header().Set("Retry-After", strconv.Itoa(m.RetryAfter))
+			}
+			msg := m.Message
+			if msg == "" {
+				msg = "Service Unavailable"
+			}
+			panic(HTTPError{Status: http.StatusServiceUnavailable, Message: msg})
+		}
+	}
+}
+
+func (m MaintenanceMode) bypass(r *http.Request) bool {
+	path := cleanPath(r.URL.Path)
+	for _, allowed := range m.Allow {
+		allowed = cleanPath(allowed)
+		if path == allowed || strings.HasPrefix(path, allowed+"/") {
+			return true
+		}
+	}
+
+	if m.Secret == "" {
+		return false
+	}
+	if secretEqual(r.Header.Get("X-Maintenance-Secret"), m.Secret) {
+		return true
+	}
+	if c, err := r.Cookie(maintenanceCookie); err == nil && secretEqual(c.Value, m.Secret) {
+		return true
+	}
+	return false
+}
+
+func secretEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func maintenanceRouter(t *testing.T, mode *MaintenanceMode) (*Router, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "down")
+	if mode != nil {
+		writeMaintenance(t, path, *mode)
+	}
+	r := NewRouter()
+	r.Use(Maintenance(path))
+	r.Get("/", func(c *Context) { c.String(http.StatusOK, "home") })
+	r.Get("/health", func(c *Context) { c.String(http.StatusOK, "ok") })
+	return r, path
+}
+
+func writeMaintenance(t *testing.T, path string, mode MaintenanceMode) {
+	t.Helper()
+	b, err := json.Marshal(mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMaintenanceDown(t *testing.T) {
+	r, _ := maintenanceRouter(t, &MaintenanceMode{Message: "Back soon", RetryAfter: 60, Allow: []string{"/health"}})
+
+	rec := serve(r, http.MethodGet, "/")
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") != "60" {
+		t.Fatalf("got %d, Retry-After %q", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	if rec := serve(r, http.MethodGet, "/health"); rec.Code != http.StatusOK {
+		t.Errorf("allowed path: got %d", rec.Code)
+	}
+}
+
+func TestMaintenanceSecretBypass(t *testing.T) {
+	r, _ := maintenanceRouter(t, &MaintenanceMode{Secret: "letmein"})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Maintenance-Secret", "letmein")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("header bypass: got %d", rec.Code)
+	}
+
+	rec = serve(r, http.MethodGet, "/letmein")
+	if rec.Code != http.StatusFound || rec.Header().Get("Location") != "/" {
+		t.Fatalf("bypass endpoint: got %d, Location %q", rec.Code, rec.Header().Get("Location"))
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != maintenanceCookie || cookies[0].Value != "letmein" || !cookies[0].HttpOnly {
+		t.Fatalf("cookies = %+v", cookies)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Body.String() != "home" {
+		t.Errorf("cookie bypass: got %d %q", rec.Code, rec.Body.String())
+	}
+
+	if rec := serve(r, http.MethodGet, "/wrong"); rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("wrong secret: got %d", rec.Code)
+	}
+}
+
+func TestMaintenanceCachesState(t *testing.T) {
+	defer func(d time.Duration) { maintenanceRecheck = d }(maintenanceRecheck)
+	maintenanceRecheck = time.Hour
+
+	r, path := maintenanceRouter(t, nil)
+	if rec := serve(r, http.MethodGet, "/"); rec.Code != http.StatusOK {
+		t.Fatalf("up: got %d", rec.Code)
+	}
+
+	// Within the recheck interval the sentinel file is not looked at again.
+	writeMaintenance(t, path, MaintenanceMode{})
+	if rec := serve(r, http.MethodGet, "/"); rec.Code != http.StatusOK {
+		t.Fatalf("cached: got %d", rec.Code)
+	}
+
+	maintenanceRecheck = 0
+	if rec := serve(r, http.MethodGet, "/"); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("down: got %d", rec.Code)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if rec := serve(r, http.MethodGet, "/"); rec.Code != http.StatusOK {
+		t.Fatalf("up again: got %d", rec.Code)
+	}
+}
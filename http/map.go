@@ -0,0 +1,30 @@
+package http
+
+// Map is a shorthand for building JSON objects in handlers.
+//
+// It encodes exactly like a plain map[string]any.
+type Map map[string]any
+
+// With sets key to value and returns the map for chaining.
+//
+// A nil Map is allocated on first use.
+func (m Map) With(key string, value any) Map {
+	if m == nil {
+		m = make(Map, 1)
+	}
+	m[key] = value
+	return m
+}
+
+// Merge copies all entries from other into m and returns m.
+//
+// Keys in other overwrite existing keys in m.
+func (m Map) Merge(other map[string]any) Map {
+	if m == nil {
+		m = make(Map, len(other))
+	}
+	for k, v := range other {
+		m[k] = v
+	}
+	return m
+}
@@ -0,0 +1,42 @@
+package http
+
+import "fmt"
+
+// MiddlewareGroup registers a named middleware stack, e.g.
+//
+//	r.MiddlewareGroup("web", Sessions(sm), CSRF(sm))
+//	r.MiddlewareGroup("api", RequestID(), RequireJSON())
+//
+// and applies it to the routes of a group with the Using option:
+//
+//	r.Group("/", web, Using("web"))
+//	r.Group("/api", api, Using("api"))
+//
+// A group must be registered before the groups that use it; registering a name
+// again replaces its stack for groups created afterwards.
+func (r *Router) MiddlewareGroup(name string, mw ...Middleware) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	if r.state.mwGroups == nil {
+		r.state.mwGroups = make(map[string][]Middleware)
+	}
+	r.state.mwGroups[name] = append([]Middleware(nil), mw...)
+}
+
+// Using applies the named middleware groups (see Router.MiddlewareGroup) to the
+// routes of a group, after the middleware it inherits. Group panics when a name
+// was never registered.
+func Using(names ...string) GroupOption {
+	return func(o *groupOptions) { o.using = append(o.using, names...) }
+}
+
+// middlewareGroup returns the stack registered as name.
+func (r *Router) middlewareGroup(name string) []Middleware {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+	mw, ok := r.state.mwGroups[name]
+	if !ok {
+		panic(fmt.Sprintf("router: unknown middleware group %q (register it with MiddlewareGroup before Using it)", name))
+	}
+	return mw
+}
@@ -0,0 +1,46 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestMiddlewareGroups(t *testing.T) {
+	sm, err := NewSessionManager("test-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasSession := func(c *Context) {
+		if c.Session() != nil {
+			c.String(http.StatusOK, "session")
+			return
+		}
+		c.String(http.StatusOK, "none")
+	}
+
+	r := NewRouter()
+	r.MiddlewareGroup("web", Sessions(sm))
+	r.MiddlewareGroup("api")
+	r.Group("/", func(r *Router) { r.Get("/home", hasSession) }, Using("web"))
+	r.Group("/api", func(r *Router) { r.Get("/users", hasSession) }, Using("api"))
+
+	rec := serve(r, http.MethodGet, "/home")
+	if rec.Body.String() != "session" || len(rec.Result().Cookies()) == 0 {
+		t.Errorf("web route: got %q, cookies %v", rec.Body.String(), rec.Result().Cookies())
+	}
+	rec = serve(r, http.MethodGet, "/api/users")
+	if rec.Body.String() != "none" || len(rec.Result().Cookies()) != 0 {
+		t.Errorf("api route: got %q, cookies %v", rec.Body.String(), rec.Result().Cookies())
+	}
+}
+
+func TestUsingUnknownGroupPanics(t *testing.T) {
+	r := NewRouter()
+	defer func() {
+		msg, _ := recover().(string)
+		if msg != `router: unknown middleware group "web" (register it with MiddlewareGroup before Using it)` {
+			t.Fatalf("recover() = %q", msg)
+		}
+	}()
+	r.Group("/", func(r *Router) {}, Using("web"))
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func echoPath(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("X-Method", req.Method)
+	_, _ = w.Write([]byte(req.URL.Path))
+}
+
+func TestMountStripsPrefix(t *testing.T) {
+	r := NewRouter()
+	r.Mount("/admin", http.HandlerFunc(echoPath))
+
+	for path, want := range map[string]string{
+		"/admin":        "/",
+		"/admin/users":  "/users",
+		"/admin/a/b":    "/a/b",
+		"/admin/files/": "/files/",
+	} {
+		rec := serve(r, http.MethodGet, path)
+		if rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Errorf("%s: got %d %q, want %q", path, rec.Code, rec.Body.String(), want)
+		}
+	}
+	if rec := serve(r, http.MethodGet, "/administrator"); rec.Code != http.StatusNotFound {
+		t.Errorf("/administrator: got %d, want 404", rec.Code)
+	}
+}
+
+func TestMountAnswersEveryMethod(t *testing.T) {
+	r := NewRouter()
+	r.Mount("/rpc", http.HandlerFunc(echoPath))
+
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodDelete, "PROPFIND"} {
+		rec := serve(r, method, "/rpc/call")
+		if rec.Code != http.StatusOK || rec.Header().Get("X-Method") != method {
+			t.Errorf("%s: got %d, method %q", method, rec.Code, rec.Header().Get("X-Method"))
+		}
+	}
+}
+
+func TestMountRoutesTakePrecedence(t *testing.T) {
+	r := NewRouter()
+	r.Mount("/", http.HandlerFunc(echoPath))
+	r.Get("/health", func(ctx *Context) { ctx.String(http.StatusOK, "ok") })
+
+	if rec := serve(r, http.MethodGet, "/health"); rec.Body.String() != "ok" {
+		t.Errorf("route: got %q", rec.Body.String())
+	}
+	if rec := serve(r, http.MethodGet, "/other"); rec.Body.String() != "/other" {
+		t.Errorf("mount: got %q", rec.Body.String())
+	}
+	if routes := r.Routes(); len(routes) != 1 || routes[0].Pattern != "/health" {
+		t.Errorf("Routes() = %+v, want only /health", routes)
+	}
+}
+
+func TestMountWithParamsAndGroups(t *testing.T) {
+	r := NewRouter()
+	r.Group("/api", func(r *Router) {
+		r.Mount("/{tenant}/files", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			_, _ = w.Write([]byte(req.URL.Path))
+		}))
+	})
+
+	rec := serve(r, http.MethodGet, "/api/acme/files/report.pdf")
+	if rec.Code != http.StatusOK || rec.Body.String() != "/report.pdf" {
+		t.Errorf("got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestMountInsideDomain(t *testing.T) {
+	r := NewRouter()
+	r.Domain("admin.example.com", func(r *Router) {
+		r.Mount("/", http.HandlerFunc(echoPath))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/dashboard", nil)
+	req.Host = "admin.example.com"
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Body.String() != "/dashboard" {
+		t.Errorf("admin host: got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := serve(r, http.MethodGet, "/dashboard"); rec.Code != http.StatusNotFound {
+		t.Errorf("other host: got %d, want 404", rec.Code)
+	}
+}
+
+func TestMountDuplicatePanics(t *testing.T) {
+	r := NewRouter()
+	r.Mount("/admin", http.HandlerFunc(echoPath))
+
+	defer func() {
+		if rec := recover(); rec != "router: duplicate mount at /admin" {
+			t.Fatalf("recover() = %v", rec)
+		}
+	}()
+	r.Mount("/admin/", http.HandlerFunc(echoPath))
+}
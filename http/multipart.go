@@ -0,0 +1,211 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+)
+
+// ErrNotMultipart is returned by Context.MultipartForm for requests whose body is
+// not multipart/form-data.
+var ErrNotMultipart = errors.New("http: request is not multipart/form-data")
+
+const (
+	// DefaultMultipartMemory is the part of a multipart body kept in memory (32 MB);
+	// the rest of the files spill to temporary files.
+	DefaultMultipartMemory int64 = 32 << 20
+
+	// DefaultMaxUploadSize caps the total size of a multipart body (64 MB).
+	DefaultMaxUploadSize int64 = 64 << 20
+
+	// DefaultMaxFileSize caps a single file saved by Context.SaveFile (32 MB).
+	DefaultMaxFileSize int64 = 32 << 20
+)
+
+// SetMultipartLimits configures how ParseMultipart reads uploads.
+//
+// maxMemory is the number of bytes kept in memory before files spill to temporary
+// files; maxSize caps the whole request body (0 means unlimited). Non-positive
+// maxMemory restores DefaultMultipartMemory.
+func (r *Router) SetMultipartLimits(maxMemory, maxSize int64) {
+	if maxMemory <= 0 {
+		maxMemory = DefaultMultipartMemory
+	}
+	if maxSize < 0 {
+		maxSize = 0
+	}
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.multipartMemory = maxMemory
+	r.state.maxUploadSize = maxSize
+}
+
+func (c *Context) multipartLimits() (maxMemory, maxSize int64) {
+	if c.router == nil {
+		return DefaultMultipartMemory, DefaultMaxUploadSize
+	}
+	c.router.state.mu.RLock()
+	defer c.router.state.mu.RUnlock()
+	return c.router.state.multipartMemory, c.router.state.maxUploadSize
+}
+
+// ParseMultipart parses multipart/form-data requests using the router's limits
+// (see SetMultipartLimits), so handlers can read Request.MultipartForm directly.
+//
+// Bodies over the size cap fail with 413 and malformed bodies with 400. Temporary
+// files are removed when the request ends. Other requests pass through untouched.
+func ParseMultipart() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) {
+			mt, _, err := mime.ParseMediaType(ctx.Request.Header.Get("Content-Type"))
+			if err != nil || mt != "multipart/form-data" {
+				next(ctx)
+				return
+			}
+
+			maxMemory, maxSize := ctx.multipartLimits()
+			if maxSize > 0 {
+				ctx.Request.Body = http.MaxBytesReader(ctx.ResponseWriter, ctx.Request.Body, maxSize)
+			}
+			if err := ctx.Request.ParseMultipartForm(maxMemory); err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					panic(HTTPError{Status: http.StatusRequestEntityTooLarge, Message: "Upload too large", Err: err})
+				}
+				panic(HTTPError{Status: http.StatusBadRequest, Message: "Invalid multipart form", Err: err})
+			}
+			defer func() {
+				if ctx.Request.MultipartForm != nil {
+					_ = ctx.Request.MultipartForm.RemoveAll()
+				}
+			}()
+			next(ctx)
+		}
+	}
+}
+
+// MultipartForm parses a multipart/form-data body with the router's limits (see
+// SetMultipartLimits) and returns the whole form, for handlers that need every
+// value and file, such as forms with dynamic field names.
+//
+// The form is parsed once per request: later calls, and calls after the
+// ParseMultipart middleware ran, return the same form. It fails with
+// ErrNotMultipart for other content types; a body over the size cap yields an
+// error wrapping *http.MaxBytesError. Temporary files are removed after the
+// response has been sent.
+func (c *Context) MultipartForm() (*multipart.Form, error) {
+	if c.Request.MultipartForm != nil {
+		return c.Request.MultipartForm, nil
+	}
+	if c.multipartErr != nil {
+		return nil, c.multipartErr
+	}
+
+	mt, _, err := mime.ParseMediaType(c.Request.Header.Get("Content-Type"))
+	if err != nil || mt != "multipart/form-data" {
+		return nil, ErrNotMultipart
+	}
+	maxMemory, maxSize := c.multipartLimits()
+	if maxSize > 0 {
+		c.Request.Body = http.MaxBytesReader(c.ResponseWriter, c.Request.Body, maxSize)
+	}
+	if err := c.Request.ParseMultipartForm(maxMemory); err != nil {
+		c.multipartErr = fmt.Errorf("http: invalid multipart form: %w", err)
+		return nil, c.multipartErr
+	}
+	form := c.Request.MultipartForm
+	c.Defer(func() { _ = form.RemoveAll() })
+	return form, nil
+}
+
+// SetMaxFileSize caps the size of a file saved by Context.SaveFile (0 means
+// unlimited; the whole body is still capped by SetMultipartLimits).
+func (r *Router) SetMaxFileSize(n int64) {
+	if n < 0 {
+		n = 0
+	}
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.maxFileSize = n
+}
+
+func (c *Context) maxFileSize() int64 {
+	if c.router == nil {
+		return DefaultMaxFileSize
+	}
+	c.router.state.mu.RLock()
+	defer c.router.state.mu.RUnlock()
+	return c.router.state.maxFileSize
+}
+
+// SaveFile writes the file uploaded in the multipart field to dst (created or
+// truncated with mode 0644) and returns the number of bytes written.
+//
+// A file over the router's file size cap (see SetMaxFileSize) is rejected with
+// 413; the cap applies to each file, while the whole body is capped by
+// SetMultipartLimits. A request whose Content-Length already exceeds the file
+// cap is rejected with 413 before its body is read. A missing field fails with 400 and a non-multipart request
+// with 415. A partially written dst is removed. Errors are HTTPErrors, so they
+// can be re-panicked.
+func (c *Context) SaveFile(field, dst string) (int64, error) {
+	limit := c.maxFileSize()
+	if limit > 0 && c.Request.ContentLength > limit {
+		return 0, fileTooLarge(field, limit)
+	}
+	form, err := c.MultipartForm()
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.Is(err, ErrNotMultipart):
+			return 0, HTTPError{Status: http.StatusUnsupportedMediaType, Message: "Content-Type must be multipart/form-data", Err: err}
+		case errors.As(err, &tooLarge):
+			return 0, HTTPError{Status: http.StatusRequestEntityTooLarge, Message: "Upload too large", Err: err}
+		}
+		return 0, HTTPError{Status: http.StatusBadRequest, Message: "Invalid multipart form", Err: err}
+	}
+	fhs := form.File[field]
+	if len(fhs) == 0 {
+		return 0, HTTPError{Status: http.StatusBadRequest, Message: "Missing file", Err: fmt.Errorf("%s: %w", field, http.ErrMissingFile)}
+	}
+	fh := fhs[0]
+	if limit > 0 && fh.Size > limit {
+		return 0, fileTooLarge(field, limit)
+	}
+
+	src, err := fh.Open()
+	if err != nil {
+		return 0, HTTPError{Status: http.StatusInternalServerError, Message: "Failed to save file", Err: err}
+	}
+	defer src.Close()
+	var r io.Reader = src
+	if limit > 0 {
+		// Enforce the cap on the bytes actually read, not only on the size the form reported.
+		r = io.LimitReader(src, limit+1)
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return 0, HTTPError{Status: http.StatusInternalServerError, Message: "Failed to save file", Err: err}
+	}
+	n, err := io.Copy(out, r)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+		return 0, HTTPError{Status: http.StatusInternalServerError, Message: "Failed to save file", Err: err}
+	}
+	if limit > 0 && n > limit {
+		_ = os.Remove(dst)
+		return 0, fileTooLarge(field, limit)
+	}
+	return n, nil
+}
+
+func fileTooLarge(field string, limit int64) HTTPError {
+	return HTTPError{Status: http.StatusRequestEntityTooLarge, Message: "File too large", Err: fmt.Errorf("http: file %q exceeds %d bytes", field, limit)}
+}
@@ -0,0 +1,136 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// multipartRequest builds a POST whose multipart body holds the given files
+// (field name -> contents) and plain fields.
+func multipartRequest(t *testing.T, files map[string]string, fields map[string]string) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := mw.WriteField(name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range files {
+		w, err := mw.CreateFormFile(name, name+".txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/upload", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return req
+}
+
+func saveFileRouter(t *testing.T, maxFileSize int64, field string) (*Router, string) {
+	t.Helper()
+	dst := filepath.Join(t.TempDir(), "saved")
+	r := NewRouter()
+	r.SetMaxFileSize(maxFileSize)
+	r.Post("/upload", func(c *Context) {
+		n, err := c.SaveFile(field, dst)
+		if err != nil {
+			panic(err)
+		}
+		c.JSON(http.StatusOK, map[string]int64{"bytes": n})
+	})
+	return r, dst
+}
+
+func TestSaveFile(t *testing.T) {
+	r, dst := saveFileRouter(t, 1<<10, "avatar")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, multipartRequest(t, map[string]string{"avatar": "hello"}, map[string]string{"name": "Ada"}))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"bytes":5}`+"\n" {
+		t.Fatalf("got %d %q", rec.Code, rec.Body.String())
+	}
+	if b, err := os.ReadFile(dst); err != nil || string(b) != "hello" {
+		t.Fatalf("saved %q, %v", b, err)
+	}
+}
+
+func TestSaveFileLimitIsPerFile(t *testing.T) {
+	// The body is well over the cap, but the saved file is not. Without a
+	// Content-Length the request cannot be rejected up front.
+	r, dst := saveFileRouter(t, 100, "small")
+	files := map[string]string{"small": "ok", "other": strings.Repeat("x", 500)}
+	req := multipartRequest(t, files, nil)
+	req.ContentLength = -1
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got %d %q", rec.Code, rec.Body.String())
+	}
+	if b, _ := os.ReadFile(dst); string(b) != "ok" {
+		t.Fatalf("saved %q", b)
+	}
+}
+
+func TestSaveFileRejectsLargeContentLengthBeforeParsing(t *testing.T) {
+	r, dst := saveFileRouter(t, 100, "big")
+	// Not even valid multipart: only the declared length may decide.
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("x", 200)))
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=none")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("got %d %q", rec.Code, rec.Body.String())
+	}
+	if _, err := os.Stat(dst); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file was written: %v", err)
+	}
+}
+
+func TestSaveFileTooLarge(t *testing.T) {
+	r, dst := saveFileRouter(t, 100, "big")
+	req := multipartRequest(t, map[string]string{"big": strings.Repeat("x", 101)}, nil)
+	req.ContentLength = -1 // reach the per-file check
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("got %d %q", rec.Code, rec.Body.String())
+	}
+	if _, err := os.Stat(dst); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("oversize file was written: %v", err)
+	}
+}
+
+func TestSaveFileErrors(t *testing.T) {
+	r, _ := saveFileRouter(t, 1<<10, "avatar")
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, multipartRequest(t, nil, map[string]string{"name": "Ada"}))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("missing file: got %d", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("{}"))
+	req.Header.Set("Content-Type", "application/json")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("json body: got %d", rec.Code)
+	}
+}
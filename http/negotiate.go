@@ -0,0 +1,127 @@
+package http
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// acceptRange is one media range of an Accept header.
+type acceptRange struct {
+	typ, sub string
+	q        float64
+	index    int
+}
+
+// parseAccept parses an Accept header. A missing header accepts anything.
+func parseAccept(header string) []acceptRange {
+	if strings.TrimSpace(header) == "" {
+		return []acceptRange{{typ: "*", sub: "*", q: 1}}
+	}
+	var out []acceptRange
+	for i, part := range strings.Split(header, ",") {
+		mt, params, _ := strings.Cut(part, ";")
+		typ, sub, ok := strings.Cut(strings.ToLower(strings.TrimSpace(mt)), "/")
+		if !ok || typ == "" || sub == "" || (typ == "*" && sub != "*") {
+			continue
+		}
+		r := acceptRange{typ: typ, sub: sub, q: 1, index: i}
+		for _, p := range strings.Split(params, ";") {
+			k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+			if strings.EqualFold(k, "q") {
+				if q, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil && q >= 0 && q <= 1 {
+					r.q = q
+				}
+			}
+		}
+		out = append(out, r)
+	}
+	return out
+}
+
+// acceptMatch is how an Accept header matches a media type: the quality of its
+// most specific matching range, how specific that range is (2 exact, 1 type/*,
+// 0 */*) and its position. ok is false when no range matches.
+type acceptMatch struct {
+	q           float64
+	specificity int
+	index       int
+	ok          bool
+}
+
+func matchAccept(ranges []acceptRange, mime string) acceptMatch {
+	typ, sub, _ := strings.Cut(strings.ToLower(strings.TrimSpace(mime)), "/")
+	var best acceptMatch
+	for _, r := range ranges {
+		var spec int
+		switch {
+		case r.typ == typ && r.sub == sub:
+			spec = 2
+		case r.typ == typ && r.sub == "*":
+			spec = 1
+		case r.typ == "*":
+			spec = 0
+		default:
+			continue
+		}
+		if !best.ok || spec > best.specificity {
+			best = acceptMatch{q: r.q, specificity: spec, index: r.index, ok: true}
+		}
+	}
+	return best
+}
+
+// Wants reports whether the client accepts mime, such as "application/json",
+// according to the Accept header: q values are honored (q=0 refuses a type) and
+// wildcards like "application/*" and "*/*" match. A request without Accept
+// accepts everything. It adds Accept to the Vary header.
+func (c *Context) Wants(mime string) bool {
+	c.Vary("Accept")
+	m := matchAccept(parseAccept(c.Request.Header.Get("Accept")), mime)
+	return m.ok && m.q > 0
+}
+
+// Negotiate calls the handler registered for the media type the client prefers,
+// according to the Accept header:
+//
+//	ctx.Negotiate(map[string]func(){
+//		"application/json": func() { ctx.OK(post) },
+//		"text/html":        func() { ctx.View("posts/show", post) },
+//	})
+//
+// The type with the highest q value wins. Ties go to the type matched by the
+// more specific range, then to the range listed first, then to the
+// alphabetically first type, so "Accept: */*" is served by "application/json"
+// when offered. It adds Accept to the Vary header and panics with an HTTPError
+// (406) when the client accepts none of the types.
+func (c *Context) Negotiate(handlers map[string]func()) {
+	c.Vary("Accept")
+	ranges := parseAccept(c.Request.Header.Get("Accept"))
+
+	offers := make([]string, 0, len(handlers))
+	for mime := range handlers {
+		offers = append(offers, mime)
+	}
+	sort.Strings(offers)
+
+	var (
+		best      string
+		bestMatch acceptMatch
+	)
+	for _, mime := range offers {
+		m := matchAccept(ranges, mime)
+		if !m.ok || m.q <= 0 {
+			continue
+		}
+		if best == "" || m.q > bestMatch.q ||
+			(m.q == bestMatch.q && (m.specificity > bestMatch.specificity ||
+				(m.specificity == bestMatch.specificity && m.index < bestMatch.index))) {
+			best, bestMatch = mime, m
+		}
+	}
+	if best == "" || handlers[best] == nil {
+		panic(HTTPError{Status: http.StatusNotAcceptable, Message: "Not Acceptable"})
+	}
+	handlers[best]()
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func negotiate(t *testing.T, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	r := NewRouter()
+	r.Get("/post", func(c *Context) {
+		c.Negotiate(map[string]func(){
+			"application/json": func() { c.String(http.StatusOK, "json") },
+			"text/html":        func() { c.String(http.StatusOK, "html") },
+		})
+	})
+	req := httptest.NewRequest(http.MethodGet, "/post", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNegotiate(t *testing.T) {
+	for accept, want := range map[string]string{
+		"application/json": "json",
+		"text/html":        "html",
+		"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8": "html",
+		"*/*":                                  "json", // ties go to the alphabetically first offer
+		"":                                     "json",
+		"text/*, application/json;q=0.5":       "html",
+		"application/*;q=0.9, text/html;q=0.8": "json",
+		"*/*;q=0.5, text/html":                 "html",
+		"*/*, application/json;q=0":            "html",
+		"text/html, application/json":          "html", // equal q: the range listed first wins
+		"application/json, text/html":          "json",
+	} {
+		rec := negotiate(t, accept)
+		if rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Errorf("Accept %q = %d %q, want %q", accept, rec.Code, rec.Body.String(), want)
+		}
+		if rec.Header().Get("Vary") != "Accept" {
+			t.Errorf("Accept %q: Vary = %q", accept, rec.Header().Get("Vary"))
+		}
+	}
+}
+
+func TestNegotiateNotAcceptable(t *testing.T) {
+	if rec := negotiate(t, "image/png"); rec.Code != http.StatusNotAcceptable {
+		t.Fatalf("status = %d, want 406", rec.Code)
+	}
+}
+
+func TestWants(t *testing.T) {
+	for _, tc := range []struct {
+		accept, mime string
+		want         bool
+	}{
+		{"application/json", "application/json", true},
+		{"application/*", "application/json", true},
+		{"text/html", "application/json", false},
+		{"*/*", "image/png", true},
+		{"*/*, image/png;q=0", "image/png", false},
+		{"", "text/html", true},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept", tc.accept)
+		c := NewContext(httptest.NewRecorder(), req, nil)
+		if got := c.Wants(tc.mime); got != tc.want {
+			t.Errorf("Accept %q Wants(%q) = %v, want %v", tc.accept, tc.mime, got, tc.want)
+		}
+	}
+}
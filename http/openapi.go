@@ -0,0 +1,159 @@
+package http
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// OpenAPIInfo is the info object of a generated OpenAPI document.
+type OpenAPIInfo struct {
+	Title       string
+	Version     string
+	Description string
+}
+
+// OpenAPI returns a minimal OpenAPI 3 document describing the registered routes,
+// ready to be encoded as JSON.
+//
+// Every route becomes an operation with its path params ({id:int} is documented
+// as an integer "id" param), its name as operationId, and the Summary and Tags
+// route options. Bodies recorded with Accepts and Returns are reflected into JSON
+// schemas (named structs go to components/schemas); routes without Returns get a
+// generic default response. Routes registered in a Domain are listed under their path.
+func (r *Router) OpenAPI(info OpenAPIInfo) map[string]any {
+	if info.Title == "" {
+		info.Title = "API"
+	}
+	if info.Version == "" {
+		info.Version = "1.0.0"
+	}
+	infoDoc := map[string]any{"title": info.Title, "version": info.Version}
+	if info.Description != "" {
+		infoDoc["description"] = info.Description
+	}
+
+	schemas := newSchemaBuilder()
+	paths := make(map[string]any)
+	for _, rt := range r.Routes() {
+		path, params := openAPIPath(rt.Pattern)
+		item, _ := paths[path].(map[string]any)
+		if item == nil {
+			item = make(map[string]any)
+			paths[path] = item
+		}
+		method := strings.ToLower(rt.Method)
+		if _, exists := item[method]; exists {
+			continue
+		}
+
+		op := map[string]any{"responses": openAPIResponses(rt.Returns, schemas)}
+		if rt.Accepts != nil {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content":  jsonContent(schemas.schema(rt.Accepts)),
+			}
+		}
+		if rt.Name != "" {
+			op["operationId"] = rt.Name
+		}
+		if rt.Summary != "" {
+			op["summary"] = rt.Summary
+		}
+		if len(rt.Tags) > 0 {
+			op["tags"] = rt.Tags
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+		item[method] = op
+	}
+
+	doc := map[string]any{
+		"openapi": "3.0.3",
+		"info":    infoDoc,
+		"paths":   paths,
+	}
+	if len(schemas.components) > 0 {
+		doc["components"] = map[string]any{"schemas": schemas.components}
+	}
+	return doc
+}
+
+func openAPIResponses(returns map[int]reflect.Type, schemas *schemaBuilder) map[string]any {
+	if len(returns) == 0 {
+		return map[string]any{"default": map[string]any{"description": "Response"}}
+	}
+	// Schemas are built in status order, so component names do not depend on map order.
+	statuses := make([]int, 0, len(returns))
+	for status := range returns {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+
+	out := make(map[string]any, len(returns))
+	for _, status := range statuses {
+		t := returns[status]
+		desc := http.StatusText(status)
+		if desc == "" {
+			desc = "Response"
+		}
+		resp := map[string]any{"description": desc}
+		if t != nil {
+			resp["content"] = jsonContent(schemas.schema(t))
+		}
+		out[strconv.Itoa(status)] = resp
+	}
+	return out
+}
+
+func jsonContent(schema map[string]any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// openAPIPath turns a route pattern into an OpenAPI path template and its path
+// parameter objects.
+func openAPIPath(pattern string) (string, []any) {
+	segs := pathSegments(pattern)
+	var params []any
+	for i, seg := range segs {
+		name, constraint, ok := parseParamSegment(seg)
+		if !ok {
+			continue
+		}
+		if n, ok := parseCatchAll(seg); ok {
+			name = n
+		}
+		segs[i] = "{" + name + "}"
+		params = append(params, map[string]any{
+			"name":     name,
+			"in":       "path",
+			"required": true,
+			"schema":   constraintSchema(constraint),
+		})
+	}
+	return "/" + strings.Join(segs, "/"), params
+}
+
+func constraintSchema(constraint string) map[string]any {
+	switch constraint {
+	case "":
+		return map[string]any{"type": "string"}
+	case "int":
+		return map[string]any{"type": "integer"}
+	case "uint":
+		return map[string]any{"type": "integer", "minimum": 0}
+	case "uuid":
+		return map[string]any{"type": "string", "format": "uuid"}
+	case "alpha":
+		return map[string]any{"type": "string", "pattern": "^[A-Za-z]+$"}
+	case "alnum":
+		return map[string]any{"type": "string", "pattern": "^[A-Za-z0-9]+$"}
+	case "slug":
+		return map[string]any{"type": "string", "pattern": "^[A-Za-z0-9_-]+$"}
+	default:
+		return map[string]any{"type": "string", "pattern": "^(?:" + constraint + ")$"}
+	}
+}
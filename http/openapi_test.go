@@ -0,0 +1,83 @@
+package http
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/jimo-go/framework/validation"
+)
+
+type Error struct {
+	Code string `json:"code"`
+}
+
+func TestOpenAPIComponentNamesAreUnique(t *testing.T) {
+	type Node struct {
+		Next *Node `json:"next,omitempty"`
+	}
+	local := func() reflect.Type {
+		type Node struct {
+			Value int `json:"value"`
+		}
+		return reflect.TypeOf(Node{})
+	}()
+
+	b := newSchemaBuilder()
+	refs := []any{
+		b.schema(reflect.TypeOf(Error{}))["$ref"],
+		b.schema(reflect.TypeOf(validation.Error{}))["$ref"],
+		b.schema(reflect.TypeOf(Error{}))["$ref"],
+		b.schema(reflect.TypeOf(Node{}))["$ref"],
+		b.schema(local)["$ref"],
+	}
+	want := []any{
+		"#/components/schemas/Error",
+		"#/components/schemas/validation.Error",
+		"#/components/schemas/Error",
+		"#/components/schemas/Node",
+		"#/components/schemas/http.Node",
+	}
+	if !reflect.DeepEqual(refs, want) {
+		t.Fatalf("refs = %v, want %v", refs, want)
+	}
+	if len(b.components) != 4 {
+		t.Errorf("components = %v", b.components)
+	}
+}
+
+func TestOpenAPIComponentNameFallsBackToNumber(t *testing.T) {
+	a := func() reflect.Type { type T struct{ A int }; return reflect.TypeOf(T{}) }()
+	c := func() reflect.Type { type T struct{ C int }; return reflect.TypeOf(T{}) }()
+	d := func() reflect.Type { type T struct{ D int }; return reflect.TypeOf(T{}) }()
+	e := func() reflect.Type { type T struct{ E int }; return reflect.TypeOf(T{}) }()
+
+	b := newSchemaBuilder()
+	var got []any
+	for _, typ := range []reflect.Type{a, c, d, e} {
+		got = append(got, b.schema(typ)["$ref"])
+	}
+	want := []any{
+		"#/components/schemas/T",
+		"#/components/schemas/http.T",
+		"#/components/schemas/github.com.jimo-go.framework.http.T",
+		"#/components/schemas/github.com.jimo-go.framework.http.T2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestOpenAPIDocumentComponents(t *testing.T) {
+	r := NewRouter()
+	r.Get("/a", func(c *Context) {}, Returns(http.StatusOK, Error{}), Returns(http.StatusBadRequest, validation.Error{}))
+
+	doc := r.OpenAPI(OpenAPIInfo{})
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	if _, ok := schemas["Error"]; !ok {
+		t.Errorf("missing Error in %v", schemas)
+	}
+	if _, ok := schemas["validation.Error"]; !ok {
+		t.Errorf("missing validation.Error in %v", schemas)
+	}
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/jimo-go/framework/ratelimit"
+)
+
+type rateLimitOptions struct {
+	store ratelimit.Store
+	key   func(*Context) string
+	name  string
+}
+
+// RateLimitOption configures RateLimit.
+type RateLimitOption func(*rateLimitOptions)
+
+// WithRateLimitStore sets the counter store (default: ratelimit.Default() at
+// request time).
+func WithRateLimitStore(s ratelimit.Store) RateLimitOption {
+	return func(o *rateLimitOptions) { o.store = s }
+}
+
+// RateLimitBy sets what requests are counted by (default: the client IP from
+// RemoteAddr), e.g. the session ID or an API key.
+func RateLimitBy(key func(*Context) string) RateLimitOption {
+	return func(o *rateLimitOptions) { o.key = key }
+}
+
+// RateLimitName names the limiter's counters in the store. Limiters with the same
+// name share their counters.
+//
+// Without a name every RateLimit call counts on its own, keyed by the order the
+// limiters were created in; set a name when instances sharing a store might
+// create them in a different order.
+func RateLimitName(name string) RateLimitOption {
+	return func(o *rateLimitOptions) { o.name = name }
+}
+
+// rateLimiters numbers unnamed limiters.
+var rateLimiters atomic.Uint64
+
+// RateLimit allows max requests per window for each client and answers the rest
+// with 429 and a Retry-After header. Every response carries X-RateLimit-Limit and
+// X-RateLimit-Remaining.
+//
+// Counters are kept in a ratelimit.Store; use a shared one to enforce the limit
+// across instances. If the store fails, the request is let through and the error
+// logged, so an outage of the store does not take the app down with it.
+func RateLimit(max int, window time.Duration, opts ...RateLimitOption) Middleware {
+	if max < 1 || window <= 0 {
+		panic("http: RateLimit needs a positive max and window")
+	}
+	o := rateLimitOptions{key: remoteIP}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	if o.name == "" {
+		o.name = "#" + strconv.FormatUint(rateLimiters.Add(1), 10)
+	}
+	prefix := fmt.Sprintf("http:%s:", o.name)
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) {
+			store := o.store
+			if store == nil {
+				store = ratelimit.Default()
+			}
+			res, err := ratelimit.Hit(store, prefix+o.key(ctx), max, window)
+			if err != nil {
+				log.Printf("http: rate limit store failed: %v", err)
+				next(ctx)
+				return
+			}
+
+			h := ctx.ResponseWriter.Header()
+			h.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+			h.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			if !res.Allowed {
+				h.Set("Retry-After", strconv.Itoa(int(math.Ceil(res.Reset.Seconds()))))
+				panic(HTTPError{Status: http.StatusTooManyRequests, Message: "Too Many Requests"})
+			}
+			next(ctx)
+		}
+	}
+}
+
+func remoteIP(ctx *Context) string {
+	host, _, err := net.SplitHostPort(ctx.Request.RemoteAddr)
+	if err != nil {
+		return ctx.Request.RemoteAddr
+	}
+	return host
+}
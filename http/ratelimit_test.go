@@ -0,0 +1,85 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jimo-go/framework/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	r := NewRouter()
+	r.Get("/", func(c *Context) { c.String(http.StatusOK, "ok") },
+		WithMiddleware(RateLimit(2, time.Minute, WithRateLimitStore(ratelimit.NewMemoryStore()))))
+
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		rec := serve(r, http.MethodGet, "/")
+		if rec.Code != want {
+			t.Fatalf("request %d: got %d, want %d", i+1, rec.Code, want)
+		}
+		if rec.Header().Get("X-RateLimit-Limit") != "2" {
+			t.Errorf("request %d: X-RateLimit-Limit %q", i+1, rec.Header().Get("X-RateLimit-Limit"))
+		}
+		if want == http.StatusTooManyRequests && rec.Header().Get("Retry-After") == "" {
+			t.Errorf("request %d: missing Retry-After", i+1)
+		}
+	}
+}
+
+func TestRateLimitersCountSeparately(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	r := NewRouter()
+	r.Post("/login", func(c *Context) { c.String(http.StatusOK, "ok") },
+		WithMiddleware(RateLimit(1, time.Minute, WithRateLimitStore(store))))
+	r.Post("/reset", func(c *Context) { c.String(http.StatusOK, "ok") },
+		WithMiddleware(RateLimit(1, time.Minute, WithRateLimitStore(store))))
+
+	if rec := serve(r, http.MethodPost, "/login"); rec.Code != http.StatusOK {
+		t.Fatalf("login: got %d", rec.Code)
+	}
+	if rec := serve(r, http.MethodPost, "/reset"); rec.Code != http.StatusOK {
+		t.Fatalf("reset shares the login counter: got %d", rec.Code)
+	}
+}
+
+func TestRateLimitNameSharesCounters(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	mw := func() Middleware {
+		return RateLimit(1, time.Minute, WithRateLimitStore(store), RateLimitName("auth"))
+	}
+	r := NewRouter()
+	r.Post("/login", func(c *Context) { c.String(http.StatusOK, "ok") }, WithMiddleware(mw()))
+	r.Post("/reset", func(c *Context) { c.String(http.StatusOK, "ok") }, WithMiddleware(mw()))
+
+	serve(r, http.MethodPost, "/login")
+	if rec := serve(r, http.MethodPost, "/reset"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("reset: got %d, want 429", rec.Code)
+	}
+}
+
+func TestRateLimitBy(t *testing.T) {
+	r := NewRouter()
+	r.Get("/", func(c *Context) { c.String(http.StatusOK, "ok") },
+		WithMiddleware(RateLimit(1, time.Minute,
+			WithRateLimitStore(ratelimit.NewMemoryStore()),
+			RateLimitBy(func(c *Context) string { return c.Request.Header.Get("X-API-Key") }))))
+
+	get := func(key string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	if got := get("a"); got != http.StatusOK {
+		t.Fatalf("a: got %d", got)
+	}
+	if got := get("b"); got != http.StatusOK {
+		t.Fatalf("b: got %d", got)
+	}
+	if got := get("a"); got != http.StatusTooManyRequests {
+		t.Fatalf("a again: got %d", got)
+	}
+}
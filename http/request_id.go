@@ -0,0 +1,61 @@
+package http
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// RequestIDHeader is the header RequestID reads and echoes.
+const RequestIDHeader = "X-Request-Id"
+
+// maxRequestIDLen bounds incoming request IDs so they are safe to log.
+const maxRequestIDLen = 128
+
+// RequestID gives every request an ID, available from Context.RequestID and
+// echoed in the X-Request-Id response header. A well-formed X-Request-Id sent by
+// the client or a proxy is kept so IDs can be correlated across services;
+// otherwise a random one is generated.
+//
+// Applying it more than once (e.g. globally and on a group) keeps the first ID.
+func RequestID() Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(ctx *Context) {
+			if ctx.requestID == "" {
+				id := ctx.Request.Header.Get(RequestIDHeader)
+				if !validRequestID(id) {
+					id = newRequestID()
+				}
+				ctx.requestID = id
+				ctx.ResponseWriter.Header().Set(RequestIDHeader, id)
+			}
+			next(ctx)
+		}
+	}
+}
+
+// RequestID returns the ID assigned by the RequestID middleware, or "" when it
+// does not run.
+func (c *Context) RequestID() string {
+	return c.requestID
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-' || c == '_' || c == '.' || c == ':' || c == '/' || c == '+' || c == '=':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -2,7 +2,13 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
+	"net"
 	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -18,6 +24,15 @@ type Middleware func(next HandlerFunc) HandlerFunc
 type routeOptions struct {
 	name       string
 	middleware []Middleware
+	doc        routeDoc
+}
+
+// routeDoc is per-route documentation metadata, used by Routes and OpenAPI.
+type routeDoc struct {
+	summary string
+	tags    []string
+	accepts reflect.Type
+	returns map[int]reflect.Type // nil values: no body
 }
 
 // RouteOption configures per-route behavior (named routes, middleware, ...).
@@ -37,30 +52,143 @@ func WithMiddleware(mw ...Middleware) RouteOption {
 	}
 }
 
+// Summary sets a one-line description of the route for generated API docs.
+func Summary(text string) RouteOption {
+	return func(o *routeOptions) {
+		o.doc.summary = text
+	}
+}
+
+// Tags groups the route under the given tags in generated API docs.
+func Tags(tags ...string) RouteOption {
+	return func(o *routeOptions) {
+		o.doc.tags = append(o.doc.tags, tags...)
+	}
+}
+
+// Accepts records the shape of the route's JSON request body, given as a value or
+// pointer of that type (Accepts(CreatePost{})). OpenAPI documents it as a schema.
+func Accepts(v any) RouteOption {
+	return func(o *routeOptions) {
+		o.doc.accepts = bodyType(v)
+	}
+}
+
+// Returns records the shape of the JSON response sent with status; pass nil for
+// responses without a body. It can be given once per status.
+func Returns(status int, v any) RouteOption {
+	return func(o *routeOptions) {
+		if o.doc.returns == nil {
+			o.doc.returns = make(map[int]reflect.Type)
+		}
+		o.doc.returns[status] = bodyType(v)
+	}
+}
+
+// bodyType returns the type of v, dereferencing one pointer level.
+func bodyType(v any) reflect.Type {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// routeNode is a node of a compressed radix tree keyed by the normalized path
+// ("/users/42", "" for the root). Static text shared by several routes is stored
+// once: "/users" and "/uploads" hang below a common "/u" node. Params and
+// catch-alls only hang off nodes that end on a segment boundary, and consume
+// the "/" that follows.
 type routeNode struct {
-	static    map[string]*routeNode
+	path     string // static text matched by this node
+	indices  string // first byte of each child's path, parallel to children
+	children []*routeNode
+
 	param     *routeNode
+	catchAll  *routeNode // {name...}: matches the rest of the path
 	paramName string
-	handler   HandlerFunc
-	mw        []Middleware
-	name      string
+	// constraint restricts the values a param node matches (nil: any).
+	constraint *paramConstraint
+	// paramNames lists the params captured on the way to this leaf, in path order;
+	// Context shares it and only allocates the values.
+	paramNames []string
+	handler    HandlerFunc
+	mw         []Middleware
+	name       string
+	pattern    string // full registered path
+	doc        routeDoc
+}
+
+// domainRoutes holds the route trees scoped to a host pattern.
+type domainRoutes struct {
+	pattern string
+	labels  []string              // host labels; "{name}" labels capture a param
+	trees   map[string]*routeNode // method -> route tree
 }
 
 type routerState struct {
-	mu    sync.RWMutex
-	trees map[string]*routeNode // method -> route tree
-	views *viewEngine
-	names map[string]string // route name -> pattern
+	mu      sync.RWMutex
+	trees   map[string]*routeNode // method -> route tree
+	domains []*domainRoutes       // most specific first
+	views   *viewEngine
+	names   map[string]string // route name -> pattern
+
+	noAutoOptions bool
+	useNumber     bool
+	debug         bool
+	redirectSlash bool
+
+	multipartMemory int64 // bytes kept in memory before spilling to temp files
+	maxUploadSize   int64 // total multipart body cap; 0 means unlimited
+	maxFileSize     int64 // per-file cap for SaveFile; 0 means unlimited
+
+	wrapJSON   func(status int, data any) any
+	wrapErrors bool
+
+	appKey []byte // application key; see SetParamKey and purposeKey
+
+	onError      *errorReporter
+	notFound     HandlerFunc
+	errorHandler func(ctx *Context, rec any)
+
+	mwGroups map[string][]Middleware // see MiddlewareGroup
 }
 
 // Router is a minimal, expressive HTTP router.
 //
-// Phase 1 intentionally supports exact-path matching only.
-// It is designed so we can later swap its matcher with a radix tree without changing the public API.
+// Routes are matched segment by segment against a radix tree per method: a static
+// segment beats a {param} segment at the same position, and the match does not
+// backtrack into the param when the static branch has no route for the rest of
+// the path. Params may carry a constraint, {id:int} or {code:[A-Z]{3}}, which is
+// validated when the route is registered and must be the same for every route
+// sharing that param. There is no limit on path depth or the number of params.
+// Matching allocates nothing besides the captured param values.
+//
+// A final {name...} segment is a catch-all: /files/{path...} matches /files/a/b/c.txt
+// with Param("path") == "a/b/c.txt" (and /files with an empty path). It is tried
+// only when no static or {param} route matches.
 type Router struct {
-	prefix string
-	state  *routerState
-	mw     []Middleware
+	prefix     string
+	namePrefix string
+	domain     *domainRoutes
+	state      *routerState
+	mw         []Middleware
+}
+
+type groupOptions struct {
+	name  string
+	using []string
+}
+
+// GroupOption configures a route group.
+type GroupOption func(*groupOptions)
+
+// GroupName prefixes the names of routes registered in the group, e.g.
+// GroupName("posts.") turns Named("index") into "posts.index".
+//
+// Prefixes of nested groups are concatenated.
+func GroupName(prefix string) GroupOption {
+	return func(o *groupOptions) { o.name = prefix }
 }
 
 // NewRouter creates a new router.
@@ -70,15 +198,114 @@ func NewRouter() *Router {
 			trees: make(map[string]*routeNode),
 			views: newViewEngine("views"),
 			names: make(map[string]string),
+
+			multipartMemory: DefaultMultipartMemory,
+			maxUploadSize:   DefaultMaxUploadSize,
+			maxFileSize:     DefaultMaxFileSize,
 		},
 	}
 }
 
+// Views returns the router's view engine, e.g. for mail.SMTPMailer.Views.
+func (r *Router) Views() Renderer {
+	return r.state.views
+}
+
 // SetViewsDir configures the directory used for Context.View().
 func (r *Router) SetViewsDir(dir string) {
 	r.state.views.SetDir(dir)
 }
 
+// WatchViews makes the view cache re-parse templates whose files changed on disk.
+func (r *Router) WatchViews(watch bool) {
+	r.state.views.SetWatch(watch)
+}
+
+// AutoOptions enables or disables automatic OPTIONS responses (enabled by default).
+//
+// When enabled, an OPTIONS request to a path without its own OPTIONS route is
+// answered with 204 and an Allow header listing the methods registered for the path.
+// The response passes through the root router's middleware, so CORS middleware can
+// decorate preflight requests.
+func (r *Router) AutoOptions(enabled bool) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.noAutoOptions = !enabled
+}
+
+// SetDebug makes error responses carry details meant for developers, such as
+// the template error behind a failed view. Keep it off in production.
+func (r *Router) SetDebug(enabled bool) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.debug = enabled
+}
+
+// RedirectTrailingSlash makes requests for a route with a trailing slash ("/users/")
+// redirect to the canonical path ("/users") instead of being served directly, so
+// every page has a single URL. GET and HEAD get a 301, other methods a 308 so the
+// method and body are kept. The query string is preserved. Off by default.
+func (r *Router) RedirectTrailingSlash(enabled bool) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.redirectSlash = enabled
+}
+
+// UseJSONNumber makes MustBind and BindAll decode JSON numbers into interface
+// values (any, map[string]any) as json.Number instead of float64.
+//
+// This keeps large integer ids exact; typed struct fields are unaffected.
+func (r *Router) UseJSONNumber(enabled bool) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.useNumber = enabled
+}
+
+// WrapJSON sets an envelope applied by Context.JSON (and OK/Created) to every
+// payload before encoding, e.g.
+//
+//	r.WrapJSON(func(status int, data any) any {
+//		return map[string]any{"data": data, "meta": map[string]any{"status": status}}
+//	})
+//
+// Context.JSONRaw bypasses it. Error responses are only wrapped after
+// WrapJSONErrors(true). A nil fn removes the envelope.
+func (r *Router) WrapJSON(fn func(status int, data any) any) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.wrapJSON = fn
+}
+
+// WrapJSONErrors makes error responses ({"message": ..., "fields": ...}) go through
+// the WrapJSON envelope too.
+func (r *Router) WrapJSONErrors(enabled bool) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.wrapErrors = enabled
+}
+
+// SetNotFound sets the handler for requests that match no route (nor any method of
+// one, which gets a 405). It runs through the global middleware like any route.
+// A nil handler restores the default plain-text "404 page not found".
+//
+//	r.SetNotFound(func(ctx *jimohttp.Context) {
+//		ctx.JSON(http.StatusNotFound, map[string]any{"message": "Not Found"})
+//	})
+func (r *Router) SetNotFound(h HandlerFunc) {
+	r.state.mu.Lock()
+	defer r.state.mu.Unlock()
+	r.state.notFound = h
+}
+
+func (r *Router) jsonWrapper(errors bool) func(status int, data any) any {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+	if errors && !r.state.wrapErrors {
+		return nil
+	}
+	return r.state.wrapJSON
+}
+
 // Use registers middleware for the current router scope.
 //
 // When called on the root router, middleware becomes effectively global.
@@ -88,7 +315,8 @@ func (r *Router) Use(mw ...Middleware) {
 
 // URL returns a route path by its name.
 //
-// Params are substituted by replacing {key} tokens.
+// Params are substituted for {key} (or {key:constraint}) segments; a {key...}
+// catch-all takes the value as is, slashes included.
 func (r *Router) URL(name string, params map[string]string) string {
 	r.state.mu.RLock()
 	pattern := r.state.names[name]
@@ -99,11 +327,15 @@ func (r *Router) URL(name string, params map[string]string) string {
 	if len(params) == 0 {
 		return pattern
 	}
-	out := pattern
-	for k, v := range params {
-		out = strings.ReplaceAll(out, "{"+k+"}", v)
+	segs := pathSegments(pattern)
+	for i, seg := range segs {
+		if name, ok := isParamSegment(seg); ok {
+			if v, ok := params[name]; ok {
+				segs[i] = v
+			}
+		}
 	}
-	return out
+	return "/" + strings.Join(segs, "/")
 }
 
 // Get registers a GET route.
@@ -116,15 +348,217 @@ func (r *Router) Post(path string, handler HandlerFunc, opts ...RouteOption) {
 	r.add(http.MethodPost, path, handler, opts...)
 }
 
+// Put registers a PUT route.
+func (r *Router) Put(path string, handler HandlerFunc, opts ...RouteOption) {
+	r.add(http.MethodPut, path, handler, opts...)
+}
+
+// Patch registers a PATCH route.
+func (r *Router) Patch(path string, handler HandlerFunc, opts ...RouteOption) {
+	r.add(http.MethodPatch, path, handler, opts...)
+}
+
+// Delete registers a DELETE route.
+func (r *Router) Delete(path string, handler HandlerFunc, opts ...RouteOption) {
+	r.add(http.MethodDelete, path, handler, opts...)
+}
+
+// Head registers a HEAD route. It is only needed to answer HEAD differently from
+// the GET route, which otherwise serves HEAD with its body discarded.
+func (r *Router) Head(path string, handler HandlerFunc, opts ...RouteOption) {
+	r.add(http.MethodHead, path, handler, opts...)
+}
+
+// Options registers an OPTIONS route, replacing the automatic OPTIONS response
+// for that path.
+func (r *Router) Options(path string, handler HandlerFunc, opts ...RouteOption) {
+	r.add(http.MethodOptions, path, handler, opts...)
+}
+
+// Route describes a route for Add.
+type Route struct {
+	Method     string
+	Path       string
+	Handler    HandlerFunc
+	Name       string
+	Middleware []Middleware
+}
+
+// Add registers routes from a table, for routes defined as data (from config or
+// generated from a spec) rather than with Get/Post:
+//
+//	r.Add([]jimohttp.Route{
+//		{Method: "GET", Path: "/posts", Handler: posts.Index, Name: "posts.index"},
+//		{Method: "POST", Path: "/posts", Handler: posts.Store, Middleware: []jimohttp.Middleware{auth}},
+//		{Method: "GET", Path: "/posts/{id:int}", Handler: posts.Show, Name: "posts.show"},
+//	})
+//
+// Routes behave exactly as if registered one by one in the current scope. Every
+// entry is validated first, so an invalid one (missing method, path or handler)
+// panics before any route of the table is registered.
+func (r *Router) Add(routes []Route) {
+	for i, rt := range routes {
+		method := strings.TrimSpace(rt.Method)
+		switch {
+		case method == "" || strings.ContainsAny(method, " \t/"):
+			panic(fmt.Sprintf("router: route %d has an invalid method %q", i, rt.Method))
+		case strings.TrimSpace(rt.Path) == "":
+			panic(fmt.Sprintf("router: route %d (%s) has no path", i, method))
+		case rt.Handler == nil:
+			panic(fmt.Sprintf("router: route %d (%s %s) has no handler", i, method, rt.Path))
+		}
+	}
+	for _, rt := range routes {
+		var opts []RouteOption
+		if rt.Name != "" {
+			opts = append(opts, Named(rt.Name))
+		}
+		if len(rt.Middleware) > 0 {
+			opts = append(opts, WithMiddleware(rt.Middleware...))
+		}
+		r.add(strings.ToUpper(strings.TrimSpace(rt.Method)), rt.Path, rt.Handler, opts...)
+	}
+}
+
 // Group creates a new router scope under prefix.
-func (r *Router) Group(prefix string, fn func(r *Router)) {
+func (r *Router) Group(prefix string, fn func(r *Router), opts ...GroupOption) {
+	if fn == nil {
+		return
+	}
+
+	var o groupOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+
+	child := &Router{
+		prefix:     joinPath(r.prefix, prefix),
+		namePrefix: r.namePrefix + o.name,
+		domain:     r.domain,
+		state:      r.state,
+		mw:         append([]Middleware(nil), r.mw...),
+	}
+	for _, name := range o.using {
+		child.mw = append(child.mw, r.middlewareGroup(name)...)
+	}
+	fn(child)
+}
+
+// Domain scopes the routes registered in fn to requests for host.
+//
+// A host label written as {name} matches any single label and is captured as a
+// route param, e.g. "{tenant}.example.com". Hosts are matched case-insensitively
+// and without the port.
+//
+// Domain routes take precedence over hostless routes: a request whose host matches
+// a domain is served by the domain's route if one matches, and falls back to the
+// hostless routes otherwise. Literal hosts are tried before wildcard ones.
+func (r *Router) Domain(host string, fn func(r *Router)) {
 	if fn == nil {
 		return
 	}
-	child := &Router{prefix: joinPath(r.prefix, prefix), state: r.state, mw: append([]Middleware(nil), r.mw...)}
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if host == "" {
+		panic("router: domain host is empty")
+	}
+
+	r.state.mu.Lock()
+	var d *domainRoutes
+	for _, existing := range r.state.domains {
+		if existing.pattern == host {
+			d = existing
+			break
+		}
+	}
+	if d == nil {
+		d = &domainRoutes{pattern: host, labels: strings.Split(host, "."), trees: make(map[string]*routeNode)}
+		for _, l := range d.labels {
+			if _, c, ok := parseParamSegment(l); ok && c != "" {
+				r.state.mu.Unlock()
+				panic("router: constraints are not supported in domain " + host)
+			}
+			if _, ok := parseCatchAll(l); ok {
+				r.state.mu.Unlock()
+				panic("router: catch-all labels are not supported in domain " + host)
+			}
+		}
+		r.state.domains = append(r.state.domains, d)
+		sort.SliceStable(r.state.domains, func(i, j int) bool {
+			return r.state.domains[i].wildcards() < r.state.domains[j].wildcards()
+		})
+	}
+	r.state.mu.Unlock()
+
+	child := &Router{
+		prefix:     r.prefix,
+		namePrefix: r.namePrefix,
+		domain:     d,
+		state:      r.state,
+		mw:         append([]Middleware(nil), r.mw...),
+	}
 	fn(child)
 }
 
+func (d *domainRoutes) wildcards() int {
+	n := 0
+	for _, l := range d.labels {
+		if _, ok := isParamSegment(l); ok {
+			n++
+		}
+	}
+	return n
+}
+
+// match reports whether host matches the domain pattern and returns captured labels.
+func (d *domainRoutes) match(host string) (routeParams, bool) {
+	labels := strings.Split(host, ".")
+	if len(labels) != len(d.labels) {
+		return routeParams{}, false
+	}
+	var params routeParams
+	for i, l := range d.labels {
+		if name, ok := isParamSegment(l); ok {
+			if labels[i] == "" {
+				return routeParams{}, false
+			}
+			params.names = append(params.names, name)
+			params.values = append(params.values, labels[i])
+			continue
+		}
+		if l != labels[i] {
+			return routeParams{}, false
+		}
+	}
+	return params, true
+}
+
+// anyMethod keys the route tree of mounted handlers, which serve every method.
+const anyMethod = "*"
+
+// mountParam is the catch-all param capturing the path below a mount.
+const mountParam = "mount"
+
+// Mount delegates every request under prefix to handler, regardless of method.
+//
+// The prefix is stripped from the request path before handler is called, which lets
+// independently built routers and plain net/http handlers be composed into one app.
+// A mount is a {mount...} catch-all route below prefix, so the prefix may contain
+// params and mounts work inside Domain. Middleware registered on the current scope
+// runs before the mounted handler. Registered routes take precedence over mounts.
+func (r *Router) Mount(prefix string, handler http.Handler) {
+	if handler == nil {
+		panic("router: mounted handler is nil")
+	}
+	r.add(anyMethod, joinPath(prefix, "{"+mountParam+"...}"), func(ctx *Context) {
+		handler.ServeHTTP(ctx.ResponseWriter, mountedRequest(ctx.Request, ctx.Param(mountParam)))
+	})
+}
+
 func (r *Router) add(method, path string, handler HandlerFunc, opts ...RouteOption) {
 	if handler == nil {
 		panic("router: handler is nil")
@@ -139,39 +573,85 @@ func (r *Router) add(method, path string, handler HandlerFunc, opts ...RouteOpti
 
 	full := joinPath(r.prefix, path)
 	segs := pathSegments(full)
+	if ro.name != "" {
+		ro.name = r.namePrefix + ro.name
+	}
 
 	r.state.mu.Lock()
 	defer r.state.mu.Unlock()
 
-	root := r.state.trees[method]
+	trees := r.state.trees
+	if r.domain != nil {
+		trees = r.domain.trees
+	}
+	root := trees[method]
 	if root == nil {
-		root = &routeNode{static: make(map[string]*routeNode)}
-		r.state.trees[method] = root
+		root = &routeNode{}
+		trees[method] = root
 	}
 
 	n := root
-	for _, seg := range segs {
-		if name, ok := isParamSegment(seg); ok {
+	var paramNames []string
+	var static strings.Builder // pending run of static segments
+	for i, seg := range segs {
+		_, isCatchAll := parseCatchAll(seg)
+		_, _, isParam := parseParamSegment(seg)
+		if !isCatchAll && !isParam {
+			static.WriteString("/" + seg)
+			if i < len(segs)-1 {
+				continue
+			}
+		}
+		if static.Len() > 0 {
+			n = n.insertStatic(static.String())
+			static.Reset()
+		}
+
+		if name, ok := parseCatchAll(seg); ok {
+			if i != len(segs)-1 {
+				panic("router: catch-all {" + name + "...} must be the last segment in " + full)
+			}
+			paramNames = append(paramNames, name)
+			if n.catchAll == nil {
+				n.catchAll = &routeNode{paramName: name}
+			} else if n.catchAll.paramName != name {
+				panic("router: conflicting catch-all name at " + full)
+			}
+			n = n.catchAll
+			continue
+		}
+		if name, raw, ok := parseParamSegment(seg); ok {
+			if strings.HasSuffix(name, "...") {
+				panic("router: constraints are not supported on catch-all {" + name + "} at " + full)
+			}
+			var c *paramConstraint
+			if raw != "" {
+				var err error
+				if c, err = parseConstraint(raw); err != nil {
+					panic("router: " + err.Error() + " in {" + name + "} at " + full)
+				}
+			}
+			paramNames = append(paramNames, name)
 			if n.param == nil {
-				n.param = &routeNode{static: make(map[string]*routeNode), paramName: name}
+				n.param = &routeNode{paramName: name, constraint: c}
 			} else if n.param.paramName != name {
 				panic("router: conflicting param name at " + full)
+			} else if n.param.constraint.String() != c.String() {
+				panic("router: conflicting constraint for {" + name + "} at " + full)
 			}
 			n = n.param
-			continue
-		}
-
-		child := n.static[seg]
-		if child == nil {
-			child = &routeNode{static: make(map[string]*routeNode)}
-			n.static[seg] = child
 		}
-		n = child
 	}
 
+	if method == anyMethod && n.handler != nil {
+		panic("router: duplicate mount at " + strings.TrimSuffix(full, "/{"+mountParam+"...}"))
+	}
 	n.handler = handler
+	n.paramNames = paramNames
 	n.mw = append(append([]Middleware(nil), r.mw...), ro.middleware...)
 	n.name = ro.name
+	n.pattern = full
+	n.doc = ro.doc
 	if ro.name != "" {
 		if existing := r.state.names[ro.name]; existing != "" && existing != full {
 			panic("router: duplicate route name " + ro.name)
@@ -181,68 +661,428 @@ func (r *Router) add(method, path string, handler HandlerFunc, opts ...RouteOpti
 }
 
 // ServeHTTP implements http.Handler.
+//
+// A path registered only under other methods gets a 405 whose Allow header lists
+// every method the path answers, per RFC 9110: a GET route also answers HEAD, and
+// OPTIONS is answered automatically unless AutoOptions(false). A lone GET route
+// thus yields "Allow: GET, HEAD, OPTIONS".
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	path := cleanPath(req.URL.Path)
-	method := req.Method
-	segs := pathSegments(path)
+	key := routeKey(path)
+	host := requestHost(req)
 
 	r.state.mu.RLock()
-	root := r.state.trees[method]
+	n, params := r.state.match(req.Method, host, key)
 	views := r.state.views
+	m, mountParams := r.state.match(anyMethod, host, key)
+	notFound := r.state.notFound
+	redirectSlash := r.state.redirectSlash
 	r.state.mu.RUnlock()
 
-	if root == nil {
-		http.NotFound(w, req)
+	if redirectSlash && path != "/" && strings.HasSuffix(req.URL.Path, "/") && r.routable(req.Method, host, key, n) {
+		status := http.StatusPermanentRedirect
+		if req.Method == http.MethodGet || req.Method == http.MethodHead {
+			status = http.StatusMovedPermanently
+		}
+		loc := canonicalLocation(req.URL)
+		r.dispatch(w, req, func(ctx *Context) {
+			ctx.ResponseWriter.Header().Set("Location", loc)
+			ctx.ResponseWriter.WriteHeader(status)
+		}, r.mw, routeParams{}, views)
 		return
 	}
 
-	n := root
-	var params map[string]string
-	for _, seg := range segs {
-		if next := n.static[seg]; next != nil {
-			n = next
-			continue
+	if n == nil && req.Method == http.MethodHead {
+		// HEAD is answered by the GET handler with the body discarded.
+		r.state.mu.RLock()
+		n, params = r.state.match(http.MethodGet, host, key)
+		r.state.mu.RUnlock()
+		if n != nil {
+			hw := &headWriter{ResponseWriter: w}
+			r.dispatch(hw, req, n.handler, n.mw, params, views)
+			hw.finish()
+			return
 		}
-		if n.param == nil {
-			http.NotFound(w, req)
+	}
+
+	if n == nil && req.Method == http.MethodOptions {
+		if allow := r.allowedMethods(host, key); len(allow) > 0 {
+			r.dispatch(w, req, func(ctx *Context) {
+				ctx.ResponseWriter.Header().Set("Allow", strings.Join(allow, ", "))
+				ctx.ResponseWriter.WriteHeader(http.StatusNoContent)
+			}, r.mw, routeParams{}, views)
 			return
 		}
-		if params == nil {
-			params = make(map[string]string, 2)
+	}
+
+	switch {
+	case n != nil:
+		r.dispatch(w, req, n.handler, n.mw, params, views)
+	case m != nil:
+		r.dispatch(w, req, m.handler, m.mw, mountParams, views)
+	default:
+		if allow := r.methodsFor(host, key); len(allow) > 0 {
+			// The path exists under other methods: 405, through the root middleware
+			// like automatic OPTIONS responses.
+			r.dispatch(w, req, func(ctx *Context) {
+				ctx.ResponseWriter.Header().Set("Allow", strings.Join(allow, ", "))
+				panic(HTTPError{Status: http.StatusMethodNotAllowed, Message: "Method Not Allowed"})
+			}, r.mw, routeParams{}, views)
+			return
+		}
+		if notFound == nil {
+			// Root middleware still runs, e.g. so maintenance mode answers unknown paths.
+			notFound = func(ctx *Context) { http.NotFound(ctx.ResponseWriter, ctx.Request) }
 		}
-		params[n.param.paramName] = seg
-		n = n.param
+		r.dispatch(w, req, notFound, r.mw, routeParams{}, views)
 	}
+}
 
-	h := n.handler
-	if h == nil {
-		http.NotFound(w, req)
-		return
+// routable reports whether a request for key would be served by a route: n is
+// the route matched for method, and HEAD falls back to GET.
+func (r *Router) routable(method, host, key string, n *routeNode) bool {
+	if n != nil {
+		return true
+	}
+	if method != http.MethodHead {
+		return false
 	}
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+	get, _ := r.state.match(http.MethodGet, host, key)
+	return get != nil
+}
 
-	if len(n.mw) > 0 {
-		h = applyMiddleware(h, n.mw)
+// canonicalLocation is u's path without trailing slashes, plus its query. Leading
+// slashes are collapsed so "//evil.example/" cannot become a protocol-relative URL.
+func canonicalLocation(u *url.URL) string {
+	loc := strings.TrimRight(u.EscapedPath(), "/")
+	loc = "/" + strings.TrimLeft(loc, "/")
+	if u.RawQuery != "" {
+		loc += "?" + u.RawQuery
+	}
+	return loc
+}
+
+func (r *Router) dispatch(w http.ResponseWriter, req *http.Request, h HandlerFunc, mw []Middleware, params routeParams, views *viewEngine) {
+	if len(mw) > 0 {
+		h = applyMiddleware(h, mw)
 	}
 
 	ctx := NewContext(w, req, views)
 	ctx.params = params
+	ctx.router = r
+	defer ctx.runDeferred()
 
 	defer func() {
 		if rec := recover(); rec != nil {
-			switch v := rec.(type) {
-			case HTTPError:
-				writeJSONError(w, v.Status, v.Message, v.Err)
-			case *HTTPError:
-				writeJSONError(w, v.Status, v.Message, v.Err)
-			default:
-				writeJSONError(w, http.StatusInternalServerError, "Internal Server Error", nil)
+			// Nobody is left to read an error response, and a disconnect is not a
+			// server failure. Other panics are reported even if the client left.
+			if ctx.disconnectPanic(rec) {
+				return
 			}
+			// Middleware that swapped the writer has unwound; answer on the real one.
+			ctx.ResponseWriter = w
+			r.handleError(ctx, rec)
+			r.reportPanic(ctx, rec)
 		}
 	}()
 
 	h(ctx)
 }
 
+// allowedMethods returns the sorted methods with a route matching key, for automatic
+// OPTIONS responses. It returns nil when automatic OPTIONS is disabled or nothing matches.
+func (r *Router) allowedMethods(host, key string) []string {
+	r.state.mu.RLock()
+	disabled := r.state.noAutoOptions
+	r.state.mu.RUnlock()
+	if disabled {
+		return nil
+	}
+	return r.methodsFor(host, key)
+}
+
+// methodsFor returns the sorted methods that can serve key, for Allow headers:
+// the registered ones, HEAD for GET routes and, unless disabled, the automatic
+// OPTIONS. It returns nil when no route matches the path.
+func (r *Router) methodsFor(host, key string) []string {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	var allow []string
+	for _, trees := range r.state.treesFor(host) {
+		for method, root := range trees {
+			if method == anyMethod {
+				continue
+			}
+			if n, _ := lookup(root, key); n != nil {
+				allow = append(allow, method)
+				if method == http.MethodGet {
+					// GET routes also answer HEAD.
+					allow = append(allow, http.MethodHead)
+				}
+			}
+		}
+	}
+	if len(allow) == 0 {
+		return nil
+	}
+	if !r.state.noAutoOptions {
+		allow = append(allow, http.MethodOptions)
+	}
+	sort.Strings(allow)
+	return dedupe(allow)
+}
+
+func dedupe(sorted []string) []string {
+	out := sorted[:0]
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// headWriter discards the body of a GET handler serving a HEAD request.
+//
+// The status line is held back until the handler returns so that Content-Length
+// can be set from the size of the discarded body when the handler did not set it.
+type headWriter struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func (w *headWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *headWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.size += len(p)
+	return len(p), nil
+}
+
+func (w *headWriter) finish() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	h := w.Header()
+	if h.Get("Content-Length") == "" && h.Get("Transfer-Encoding") == "" && w.size > 0 {
+		h.Set("Content-Length", strconv.Itoa(w.size))
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// insertStatic returns the node for text below n, splitting nodes where text
+// diverges from an existing path.
+func (n *routeNode) insertStatic(text string) *routeNode {
+	for {
+		i := strings.IndexByte(n.indices, text[0])
+		if i < 0 {
+			child := &routeNode{path: text}
+			n.indices += text[:1]
+			n.children = append(n.children, child)
+			return child
+		}
+
+		child := n.children[i]
+		l := commonPrefix(child.path, text)
+		if l < len(child.path) {
+			// Split child: it keeps the shared prefix, the rest moves one level down.
+			rest := new(routeNode)
+			*rest = *child
+			rest.path = child.path[l:]
+			*child = routeNode{path: child.path[:l], indices: rest.path[:1], children: []*routeNode{rest}}
+		}
+		if l == len(text) {
+			return child
+		}
+		n, text = child, text[l:]
+	}
+}
+
+func commonPrefix(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// find matches key below the root n segment by segment, collecting param values
+// into vals. A segment that exists as static text below the current position is
+// always taken, even when the rest of the path then has no route: static beats
+// {param} without backtracking, so /users/new/posts does not fall back to
+// /users/{id}/posts. The deepest catch-all passed on the way down is the fallback
+// when the path dead-ends.
+func (n *routeNode) find(key string, vals []string) (*routeNode, []string) {
+	off := len(n.path) // how much of n.path the path matched so far
+	var (
+		fallback *routeNode
+		fbVals   []string
+	)
+	useFallback := func() (*routeNode, []string) {
+		if fallback == nil {
+			return nil, vals
+		}
+		return fallback, fbVals
+	}
+
+	rest := key
+	for rest != "" {
+		seg := rest[1:]
+		if end := strings.IndexByte(seg, '/'); end >= 0 {
+			seg = seg[:end]
+		}
+		atNode := off == len(n.path)
+		if atNode && n.catchAll != nil && n.catchAll.handler != nil {
+			fallback = n.catchAll
+			fbVals = append(vals[:len(vals):len(vals)], rest[1:])
+		}
+		if m, moff, ok := n.walk(off, rest[:1+len(seg)]); ok && m.segmentEnd(moff) {
+			n, off = m, moff
+			rest = rest[1+len(seg):]
+			continue
+		}
+		if p := n.param; atNode && p != nil && (p.constraint == nil || p.constraint.match(seg)) {
+			vals = append(vals, seg)
+			n, off = p, len(p.path)
+			rest = rest[1+len(seg):]
+			continue
+		}
+		return useFallback()
+	}
+
+	if off == len(n.path) {
+		if n.handler != nil {
+			return n, vals
+		}
+		// An empty remainder still matches a catch-all: /files matches /files/{path...}.
+		if c := n.catchAll; c != nil && c.handler != nil {
+			return c, append(vals, "")
+		}
+	}
+	return useFallback()
+}
+
+// walk consumes the static text from offset off of n's path, descending into
+// children as needed, and returns where it ended.
+func (n *routeNode) walk(off int, text string) (*routeNode, int, bool) {
+	for text != "" {
+		if off < len(n.path) {
+			l := commonPrefix(n.path[off:], text)
+			if l == 0 || (l < len(text) && off+l < len(n.path)) {
+				return nil, 0, false
+			}
+			off += l
+			text = text[l:]
+			continue
+		}
+		i := strings.IndexByte(n.indices, text[0])
+		if i < 0 {
+			return nil, 0, false
+		}
+		n, off = n.children[i], 0
+	}
+	return n, off, true
+}
+
+// segmentEnd reports whether a registered route has a segment ending at offset
+// off of n's path, i.e. the route ends there or continues with another segment.
+func (n *routeNode) segmentEnd(off int) bool {
+	if off < len(n.path) {
+		return n.path[off] == '/'
+	}
+	return n.handler != nil || n.param != nil || n.catchAll != nil || strings.IndexByte(n.indices, '/') >= 0
+}
+
+// valuesPool recycles the scratch slices lookup collects param values in.
+var valuesPool = sync.Pool{
+	New: func() any {
+		s := make([]string, 0, 8)
+		return &s
+	},
+}
+
+// lookup matches key (see routeKey) against the tree and returns the node with a
+// handler and the captured param values, in the order of its paramNames.
+func lookup(root *routeNode, key string) (*routeNode, []string) {
+	if root == nil {
+		return nil, nil
+	}
+	sp := valuesPool.Get().(*[]string)
+	n, vals := root.find(key, (*sp)[:0])
+	var values []string
+	if n != nil && len(vals) > 0 {
+		values = make([]string, len(vals))
+		copy(values, vals)
+	}
+	*sp = vals[:0]
+	valuesPool.Put(sp)
+	return n, values
+}
+
+// match finds the route for method and key, trying domains matching host before
+// the hostless routes. Host params are merged into the route params.
+// The caller must hold s.mu.
+func (s *routerState) match(method, host, key string) (*routeNode, routeParams) {
+	for _, d := range s.domains {
+		hostParams, ok := d.match(host)
+		if !ok {
+			continue
+		}
+		if n, values := lookup(d.trees[method], key); n != nil {
+			// Host params come first so they win over same-named path params.
+			return n, routeParams{
+				names:  append(hostParams.names, n.paramNames...),
+				values: append(hostParams.values, values...),
+			}
+		}
+	}
+	n, values := lookup(s.trees[method], key)
+	if n == nil {
+		return nil, routeParams{}
+	}
+	return n, routeParams{names: n.paramNames, values: values}
+}
+
+// treesFor returns the route trees that may serve host: matching domains, then the
+// hostless routes. The caller must hold s.mu.
+func (s *routerState) treesFor(host string) []map[string]*routeNode {
+	out := make([]map[string]*routeNode, 0, 2)
+	for _, d := range s.domains {
+		if _, ok := d.match(host); ok {
+			out = append(out, d.trees)
+		}
+	}
+	return append(out, s.trees)
+}
+
+// mountedRequest returns a shallow copy of req whose URL path is rest, the part
+// below the mount prefix. A trailing slash of the original path is kept, since
+// handlers such as http.FileServer depend on it.
+func mountedRequest(req *http.Request, rest string) *http.Request {
+	path := "/" + rest
+	if rest != "" && strings.HasSuffix(req.URL.Path, "/") {
+		path += "/"
+	}
+
+	r2 := new(http.Request)
+	*r2 = *req
+	u := *req.URL
+	u.Path = path
+	u.RawPath = ""
+	r2.URL = &u
+	return r2
+}
+
 func applyMiddleware(h HandlerFunc, chain []Middleware) HandlerFunc {
 	out := h
 	for i := len(chain) - 1; i >= 0; i-- {
@@ -250,26 +1090,36 @@ func applyMiddleware(h HandlerFunc, chain []Middleware) HandlerFunc {
 		if mw == nil {
 			continue
 		}
-		out = mw(out)
+		out = mw(skipHalted(out))
 	}
 	return out
 }
 
-type fieldErrorer interface {
-	FieldErrors() map[string]string
+// skipHalted stops the chain at next once Context.Halt was called.
+func skipHalted(next HandlerFunc) HandlerFunc {
+	return func(ctx *Context) {
+		if ctx.halted {
+			return
+		}
+		next(ctx)
+	}
 }
 
-func writeJSONError(w http.ResponseWriter, status int, message string, err error) {
+func writeJSONError(w http.ResponseWriter, status int, message string, err error, wrap func(int, any) any) {
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(status)
 
 	payload := map[string]any{"message": message}
 	if err != nil {
-		if fe, ok := err.(fieldErrorer); ok {
+		if fe, ok := err.(FieldErrorer); ok {
 			payload["fields"] = fe.FieldErrors()
 		}
 	}
-	_ = json.NewEncoder(w).Encode(payload)
+	var out any = payload
+	if wrap != nil {
+		out = wrap(status, payload)
+	}
+	_ = json.NewEncoder(w).Encode(out)
 }
 
 func joinPath(prefix, path string) string {
@@ -302,16 +1152,30 @@ func cleanPath(p string) string {
 	return p
 }
 
-func pathSegments(path string) []string {
+// routeKey normalizes a cleaned request path into the key matched against the
+// route trees: empty segments are dropped and the root is "".
+func routeKey(path string) string {
 	if path == "/" {
-		return nil
+		return ""
+	}
+	if !strings.Contains(path, "//") {
+		return path
 	}
+	segs := pathSegments(path)
+	if len(segs) == 0 {
+		return ""
+	}
+	return "/" + strings.Join(segs, "/")
+}
+
+// pathSegments returns the non-empty segments of path.
+func pathSegments(path string) []string {
+	segs := make([]string, 0, 8)
 	path = strings.Trim(path, "/")
 	if path == "" {
-		return nil
+		return segs
 	}
 
-	segs := make([]string, 0, 8)
 	start := 0
 	for i := 0; i < len(path); i++ {
 		if path[i] == '/' {
@@ -328,15 +1192,9 @@ func pathSegments(path string) []string {
 }
 
 func isParamSegment(seg string) (string, bool) {
-	if len(seg) < 3 {
-		return "", false
-	}
-	if seg[0] != '{' || seg[len(seg)-1] != '}' {
-		return "", false
-	}
-	name := seg[1 : len(seg)-1]
-	if name == "" || strings.ContainsAny(name, "/{}") {
-		return "", false
+	if name, ok := parseCatchAll(seg); ok {
+		return name, true
 	}
-	return name, true
+	name, _, ok := parseParamSegment(seg)
+	return name, ok
 }
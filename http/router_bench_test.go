@@ -0,0 +1,93 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// paramRoutes registers routes with up to depth params each, plus static
+// siblings at every level.
+func paramRoutes(depth int) []string {
+	var out []string
+	for i := 0; i < 50; i++ {
+		path := fmt.Sprintf("/r%d", i)
+		for d := 1; d <= depth; d++ {
+			path += fmt.Sprintf("/{p%d}/s%d", d, d)
+			out = append(out, path)
+			out = append(out, fmt.Sprintf("/r%d/static%d", i, d))
+		}
+	}
+	return out
+}
+
+const deepParamPath = "/r49/a/s1/b/s2/c/s3/d/s4/e/s5/f/s6/g/s7/h/s8"
+
+func TestParamLookupAllocations(t *testing.T) {
+	r := NewRouter()
+	ref := newRefNode()
+	for _, p := range paramRoutes(8) {
+		r.Get(p, func(*Context) {})
+		ref.add(p)
+	}
+	root := r.state.trees[http.MethodGet]
+	key := routeKey(deepParamPath)
+
+	radix := testing.AllocsPerRun(200, func() { lookup(root, key) })
+	old := testing.AllocsPerRun(200, func() { ref.lookup(deepParamPath) })
+	// The radix tree only allocates the captured values; the old tree also
+	// allocated the segment slice and a params map.
+	if radix > 1 {
+		t.Fatalf("radix lookup of 8 params allocates %.0f times, want at most 1", radix)
+	}
+	if radix >= old {
+		t.Fatalf("radix lookup allocates %.0f times, old matcher %.0f", radix, old)
+	}
+}
+
+func BenchmarkParamHeavyServeHTTP(b *testing.B) {
+	for _, depth := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("params=%d", depth), func(b *testing.B) {
+			r := NewRouter()
+			for _, p := range paramRoutes(depth) {
+				r.Get(p, func(*Context) {})
+			}
+			path := "/r49"
+			for d := 1; d <= depth; d++ {
+				path += fmt.Sprintf("/v%d/s%d", d, d)
+			}
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			w := httptest.NewRecorder()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				r.ServeHTTP(w, req)
+			}
+		})
+	}
+}
+
+func BenchmarkParamHeavyLookup(b *testing.B) {
+	r := NewRouter()
+	ref := newRefNode()
+	for _, p := range paramRoutes(8) {
+		r.Get(p, func(*Context) {})
+		ref.add(p)
+	}
+	root := r.state.trees[http.MethodGet]
+	key := routeKey(deepParamPath)
+
+	b.Run("radix", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			lookup(root, key)
+		}
+	})
+	b.Run("old", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ref.lookup(deepParamPath)
+		}
+	})
+}
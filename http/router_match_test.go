@@ -0,0 +1,255 @@
+package http
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// refNode is the segment tree the router used before the radix tree. It is kept
+// here as the reference the radix matcher must agree with.
+type refNode struct {
+	static     map[string]*refNode
+	param      *refNode
+	catchAll   *refNode
+	constraint *paramConstraint
+	pattern    string
+}
+
+func newRefNode() *refNode { return &refNode{static: make(map[string]*refNode)} }
+
+func (root *refNode) add(pattern string) {
+	n := root
+	for _, seg := range pathSegments(pattern) {
+		if _, ok := parseCatchAll(seg); ok {
+			if n.catchAll == nil {
+				n.catchAll = newRefNode()
+			}
+			n = n.catchAll
+			continue
+		}
+		if _, raw, ok := parseParamSegment(seg); ok {
+			if n.param == nil {
+				n.param = newRefNode()
+				if raw != "" {
+					n.param.constraint, _ = parseConstraint(raw)
+				}
+			}
+			n = n.param
+			continue
+		}
+		child := n.static[seg]
+		if child == nil {
+			child = newRefNode()
+			n.static[seg] = child
+		}
+		n = child
+	}
+	n.pattern = pattern
+}
+
+func (root *refNode) lookup(path string) (string, []string) {
+	segs := pathSegments(path)
+	n := root
+	var vals []string
+	var (
+		fallback     *refNode
+		fallbackVals []string
+	)
+	useFallback := func() bool {
+		if fallback == nil {
+			return false
+		}
+		n, vals = fallback, fallbackVals
+		return true
+	}
+	for i, seg := range segs {
+		if n.catchAll != nil && n.catchAll.pattern != "" {
+			fallback = n.catchAll
+			fallbackVals = append(vals[:len(vals):len(vals)], strings.Join(segs[i:], "/"))
+		}
+		if next := n.static[seg]; next != nil {
+			n = next
+			continue
+		}
+		if n.param != nil && (n.param.constraint == nil || n.param.constraint.match(seg)) {
+			vals = append(vals, seg)
+			n = n.param
+			continue
+		}
+		if !useFallback() {
+			return "", nil
+		}
+		break
+	}
+	if n.pattern == "" {
+		if c := n.catchAll; c != nil && c.pattern != "" {
+			n, vals = c, append(vals, "")
+		} else if !useFallback() {
+			return "", nil
+		}
+	}
+	return n.pattern, vals
+}
+
+func radixLookup(r *Router, path string) (string, []string) {
+	n, vals := lookup(r.state.trees[http.MethodGet], routeKey(cleanPath(path)))
+	if n == nil {
+		return "", nil
+	}
+	return n.pattern, vals
+}
+
+func assertSameMatch(t *testing.T, r *Router, ref *refNode, path string) {
+	t.Helper()
+	gotPattern, gotVals := radixLookup(r, path)
+	wantPattern, wantVals := ref.lookup(path)
+	if gotPattern != wantPattern || fmt.Sprint(gotVals) != fmt.Sprint(wantVals) {
+		t.Fatalf("%s: radix matched %q %q, reference %q %q", path, gotPattern, gotVals, wantPattern, wantVals)
+	}
+}
+
+func TestStaticSegmentDoesNotBacktrackIntoParam(t *testing.T) {
+	r := NewRouter()
+	ok := func(c *Context) { c.String(http.StatusOK, c.Param("id")) }
+	r.Get("/users/new", ok)
+	r.Get("/users/{id}/posts", ok)
+
+	for path, want := range map[string]int{
+		"/users/new":       http.StatusOK,
+		"/users/42/posts":  http.StatusOK,
+		"/users/new/posts": http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != want {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, want)
+		}
+	}
+}
+
+func TestRadixMatchesReference(t *testing.T) {
+	patterns := []string{
+		"/", "/users", "/users/new", "/users/newest", "/users/{id}", "/users/{id}/posts",
+		"/users/{id}/posts/{post:int}", "/uploads/{path...}", "/files/{path...}",
+		"/files/static/app.js", "/api/v1/items", "/api/{version}/users",
+	}
+	r := NewRouter()
+	ref := newRefNode()
+	for _, p := range patterns {
+		r.Get(p, func(*Context) {})
+		ref.add(p)
+	}
+	for _, path := range []string{
+		"/", "/users", "/users/", "/users/new", "/users/newe", "/users/newest", "/users/ne",
+		"/users/7", "/users/new/posts", "/users/7/posts", "/users/7/posts/3", "/users/7/posts/x",
+		"/uploads", "/uploads/a/b.txt", "/files/static/app.js", "/files/static/other.js",
+		"/files/a/b/c.txt", "/api/v1/items", "/api/v1/users", "/api/v2/users", "/api/v1",
+		"//users//7", "/nope",
+	} {
+		assertSameMatch(t, r, ref, path)
+	}
+}
+
+var fuzzSegments = []string{"users", "user", "new", "newest", "n", "posts", "p", "42"}
+
+// randomRoutes registers up to 12 routes built from a small vocabulary, so that
+// prefixes overlap and radix nodes get split mid-segment.
+func randomRoutes(rng *rand.Rand) (*Router, *refNode) {
+	r := NewRouter()
+	ref := newRefNode()
+	for i := rng.Intn(12) + 1; i > 0; i-- {
+		depth := rng.Intn(4) + 1
+		segs := make([]string, depth)
+		for d := range segs {
+			switch x := rng.Intn(10); {
+			case x == 0 && d == depth-1:
+				segs[d] = "{rest...}"
+			case x < 3:
+				segs[d] = fmt.Sprintf("{p%d}", d)
+			default:
+				segs[d] = fuzzSegments[rng.Intn(len(fuzzSegments))]
+			}
+		}
+		pattern := "/" + strings.Join(segs, "/")
+		r.Get(pattern, func(*Context) {})
+		ref.add(pattern)
+	}
+	return r, ref
+}
+
+func randomPath(rng *rand.Rand) string {
+	segs := make([]string, rng.Intn(5))
+	for i := range segs {
+		segs[i] = fuzzSegments[rng.Intn(len(fuzzSegments))]
+	}
+	return "/" + strings.Join(segs, "/")
+}
+
+func FuzzRadixMatchesReference(f *testing.F) {
+	f.Add(int64(1), "/users/new/posts")
+	f.Add(int64(2), "/users/newest")
+	f.Add(int64(3), "/n/42/p")
+	f.Fuzz(func(t *testing.T, seed int64, path string) {
+		rng := rand.New(rand.NewSource(seed))
+		r, ref := randomRoutes(rng)
+		assertSameMatch(t, r, ref, path)
+		for i := 0; i < 20; i++ {
+			assertSameMatch(t, r, ref, randomPath(rng))
+		}
+	})
+}
+
+func TestRadixMatchesReferenceRandom(t *testing.T) {
+	for seed := int64(0); seed < 500; seed++ {
+		rng := rand.New(rand.NewSource(seed))
+		r, ref := randomRoutes(rng)
+		for i := 0; i < 50; i++ {
+			assertSameMatch(t, r, ref, randomPath(rng))
+		}
+	}
+}
+
+// benchRoutes returns 10k patterns: 5k static and 5k with a param.
+func benchRoutes() []string {
+	out := make([]string, 0, 10000)
+	for i := 0; i < 5000; i++ {
+		out = append(out, fmt.Sprintf("/api/v1/resource%d/list", i))
+		out = append(out, fmt.Sprintf("/api/v1/resource%d/{id}/detail", i))
+	}
+	return out
+}
+
+func BenchmarkRouter10kRoutes(b *testing.B) {
+	r := NewRouter()
+	for _, p := range benchRoutes() {
+		r.Get(p, func(*Context) {})
+	}
+	root := r.state.trees[http.MethodGet]
+	keys := []string{"/api/v1/resource4999/list", "/api/v1/resource2500/42/detail"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if n, _ := lookup(root, keys[i%2]); n == nil {
+			b.Fatal("no match")
+		}
+	}
+}
+
+func BenchmarkReference10kRoutes(b *testing.B) {
+	ref := newRefNode()
+	for _, p := range benchRoutes() {
+		ref.add(p)
+	}
+	paths := []string{"/api/v1/resource4999/list", "/api/v1/resource2500/42/detail"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if p, _ := ref.lookup(paths[i%2]); p == "" {
+			b.Fatal("no match")
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func serve(r *Router, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	r := NewRouter()
+	r.Get("/users/{id}", func(c *Context) { c.String(http.StatusOK, c.Param("id")) })
+
+	rec := serve(r, http.MethodPost, "/users/42")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST /users/42 = %d, want 405", rec.Code)
+	}
+	// GET implies HEAD, and OPTIONS is answered automatically.
+	if got := rec.Header().Get("Allow"); got != "GET, HEAD, OPTIONS" {
+		t.Fatalf("Allow = %q, want %q", got, "GET, HEAD, OPTIONS")
+	}
+
+	if rec := serve(r, http.MethodPost, "/posts/42"); rec.Code != http.StatusNotFound {
+		t.Fatalf("POST /posts/42 = %d, want 404", rec.Code)
+	}
+}
+
+func TestMethodNotAllowedWithoutAutoOptions(t *testing.T) {
+	r := NewRouter()
+	r.AutoOptions(false)
+	r.Get("/users/{id}", func(c *Context) {})
+	r.Delete("/users/{id}", func(c *Context) {})
+
+	rec := serve(r, http.MethodPost, "/users/42")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST /users/42 = %d, want 405", rec.Code)
+	}
+	if got := rec.Header().Get("Allow"); got != "DELETE, GET, HEAD" {
+		t.Fatalf("Allow = %q, want %q", got, "DELETE, GET, HEAD")
+	}
+}
+
+func TestAutoOptions(t *testing.T) {
+	r := NewRouter()
+	r.Get("/users", func(c *Context) {})
+	r.Post("/users", func(c *Context) {})
+
+	rec := serve(r, http.MethodOptions, "/users")
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("OPTIONS /users = %d, want 204", rec.Code)
+	}
+	if got := rec.Header().Get("Allow"); got != "GET, HEAD, OPTIONS, POST" {
+		t.Fatalf("Allow = %q", got)
+	}
+}
+
+func TestVerbsDoNotCollide(t *testing.T) {
+	r := NewRouter()
+	echo := func(c *Context) {
+		c.ResponseWriter.Header().Set("X-Handler", c.Request.Method)
+		c.String(http.StatusOK, c.Request.Method+" "+c.Param("id"))
+	}
+	r.Get("/users/{id}", echo)
+	r.Post("/users/{id}", echo)
+	r.Put("/users/{id}", echo)
+	r.Patch("/users/{id}", echo)
+	r.Delete("/users/{id}", echo)
+	r.Head("/users/{id}", echo)
+	r.Options("/users/{id}", echo)
+
+	for _, method := range []string{
+		http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodHead, http.MethodOptions,
+	} {
+		rec := serve(r, method, "/users/7")
+		if rec.Code != http.StatusOK || rec.Header().Get("X-Handler") != method {
+			t.Errorf("%s /users/7 = %d handled by %q", method, rec.Code, rec.Header().Get("X-Handler"))
+		}
+		if method != http.MethodHead && rec.Body.String() != method+" 7" {
+			t.Errorf("%s /users/7 body = %q", method, rec.Body.String())
+		}
+	}
+}
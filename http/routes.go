@@ -0,0 +1,93 @@
+package http
+
+import (
+	"reflect"
+	"sort"
+)
+
+// RouteInfo describes a registered route.
+type RouteInfo struct {
+	Method  string
+	Pattern string // full path, including group prefixes and {param:constraint} segments
+	Host    string // host pattern for routes registered in a Domain, else empty
+	Name    string
+	// Middleware is the number of middleware wrapping the route, not counting the
+	// router-wide middleware added after it was registered.
+	Middleware int
+	Summary    string
+	Tags       []string
+	// Accepts and Returns are the body types recorded with the Accepts and Returns
+	// route options (nil when not set; a nil Returns entry means no body).
+	Accepts reflect.Type
+	Returns map[int]reflect.Type
+}
+
+// Routes returns the registered routes sorted by host, pattern and method. Mounted
+// handlers and automatic OPTIONS/HEAD responses are not listed.
+func (r *Router) Routes() []RouteInfo {
+	r.state.mu.RLock()
+	defer r.state.mu.RUnlock()
+
+	var out []RouteInfo
+	collect := func(host string, trees map[string]*routeNode) {
+		for method, root := range trees {
+			if method == anyMethod {
+				continue
+			}
+			walkRoutes(root, func(n *routeNode) {
+				out = append(out, RouteInfo{
+					Method:     method,
+					Pattern:    n.pattern,
+					Host:       host,
+					Name:       n.name,
+					Middleware: len(n.mw),
+					Summary:    n.doc.summary,
+					Tags:       append([]string(nil), n.doc.tags...),
+					Accepts:    n.doc.accepts,
+					Returns:    copyReturns(n.doc.returns),
+				})
+			})
+		}
+	}
+	collect("", r.state.trees)
+	for _, d := range r.state.domains {
+		collect(d.pattern, d.trees)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+		if a.Pattern != b.Pattern {
+			return a.Pattern < b.Pattern
+		}
+		return a.Method < b.Method
+	})
+	return out
+}
+
+func copyReturns(in map[int]reflect.Type) map[int]reflect.Type {
+	if in == nil {
+		return nil
+	}
+	out := make(map[int]reflect.Type, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
+func walkRoutes(n *routeNode, fn func(*routeNode)) {
+	if n == nil {
+		return
+	}
+	if n.handler != nil {
+		fn(n)
+	}
+	for _, child := range n.children {
+		walkRoutes(child, fn)
+	}
+	walkRoutes(n.param, fn)
+	walkRoutes(n.catchAll, fn)
+}
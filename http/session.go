@@ -3,27 +3,40 @@ package http
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 )
 
-// Session represents per-client state stored in an encrypted cookie.
+// Session represents per-client state stored in an encrypted cookie, or in a
+// SessionStore when the SessionManager has one.
 //
-// Phase 1 implements a secure cookie-backed session (no server storage).
+// Values are JSON-encoded between requests, so they come back as JSON types:
+// numbers become float64 and structs become map[string]any. Use GetInt,
+// GetString and GetBool to read scalars regardless of the round-trip.
 type Session struct {
 	Values   map[string]any `json:"values"`
 	Flashes  map[string]any `json:"flashes,omitempty"`
 	CSRF     string         `json:"csrf"`
+	SID      string         `json:"sid"`
 	IssuedAt int64          `json:"iat"`
 
 	dirty bool `json:"-"`
+
+	// storeID is the secret key of a session kept in a SessionStore; oldStoreID
+	// is destroyed on the next save after Regenerate.
+	storeID    string
+	oldStoreID string
 }
 
 func newSession() *Session {
@@ -43,6 +56,61 @@ func (s *Session) Get(key string) any {
 	return s.Values[key]
 }
 
+// GetInt returns an integer value from the session.
+//
+// It accepts values stored as any integer type, whole float64s (as decoded
+// from JSON), json.Number and numeric strings.
+func (s *Session) GetInt(key string) (int, bool) {
+	switch x := s.Get(key).(type) {
+	case int:
+		return x, true
+	case int64:
+		return int(x), true
+	case int32:
+		return int(x), true
+	case float64:
+		if x != math.Trunc(x) {
+			return 0, false
+		}
+		return int(x), true
+	case json.Number:
+		n, err := strconv.Atoi(string(x))
+		return n, err == nil
+	case string:
+		n, err := strconv.Atoi(x)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// GetString returns a string value from the session.
+func (s *Session) GetString(key string) (string, bool) {
+	switch x := s.Get(key).(type) {
+	case string:
+		return x, true
+	case json.Number:
+		return string(x), true
+	default:
+		return "", false
+	}
+}
+
+// GetBool returns a boolean value from the session.
+//
+// It also accepts strings understood by strconv.ParseBool.
+func (s *Session) GetBool(key string) (bool, bool) {
+	switch x := s.Get(key).(type) {
+	case bool:
+		return x, true
+	case string:
+		b, err := strconv.ParseBool(x)
+		return b, err == nil
+	default:
+		return false, false
+	}
+}
+
 // Put sets a value in the session.
 func (s *Session) Put(key string, value any) {
 	if s == nil {
@@ -55,6 +123,55 @@ func (s *Session) Put(key string, value any) {
 	s.dirty = true
 }
 
+// Forget removes a value from the session.
+func (s *Session) Forget(key string) {
+	if s == nil {
+		return
+	}
+	if _, ok := s.Values[key]; !ok {
+		return
+	}
+	delete(s.Values, key)
+	s.dirty = true
+}
+
+// ID returns a random identifier that stays the same for the session's lifetime,
+// for logging, analytics or rate limiting keyed by session. It is not a secret
+// and rotates with Regenerate.
+func (s *Session) ID() string {
+	if s == nil {
+		return ""
+	}
+	return s.SID
+}
+
+// Regenerate rotates the session's security tokens and ID.
+//
+// Call it whenever the authentication state changes (login, logout) to prevent
+// session fixation. Values are kept.
+func (s *Session) Regenerate() {
+	if s == nil {
+		return
+	}
+	s.CSRF = ""
+	s.SID = ""
+	if s.storeID != "" {
+		s.oldStoreID = s.storeID
+		s.storeID = ""
+	}
+	ensureTokens(s)
+}
+
+// Invalidate removes all values and flashes and regenerates the session.
+func (s *Session) Invalidate() {
+	if s == nil {
+		return
+	}
+	s.Values = make(map[string]any)
+	s.Flashes = make(map[string]any)
+	s.Regenerate()
+}
+
 // Flash sets a value that is meant to be used once.
 func (s *Session) Flash(key string, value any) {
 	if s == nil {
@@ -88,6 +205,19 @@ type SessionManager struct {
 	HTTPOnly   bool
 	SameSite   http.SameSite
 	MaxAge     time.Duration
+
+	// Codec serializes the session payload; nil means JSONCodec.
+	// Changing it invalidates existing session cookies.
+	Codec SessionCodec
+
+	// Store keeps sessions on the server, so the cookie only carries an
+	// encrypted session ID and payloads are not limited by the 4KB cookie size.
+	// Nil (the default) keeps the whole session in the cookie.
+	Store SessionStore
+}
+
+func (m *SessionManager) codec() SessionCodec {
+	return sessionCodec(m.Codec)
 }
 
 func NewSessionManager(appKey string) (*SessionManager, error) {
@@ -127,18 +257,16 @@ func deriveKey(appKey string) ([]byte, error) {
 }
 
 func (m *SessionManager) load(r *http.Request) *Session {
-	c, err := r.Cookie(m.CookieName)
-	if err != nil {
-		s := newSession()
-		ensureCSRF(s)
-		return s
+	var s *Session
+	if c, err := r.Cookie(m.CookieName); err == nil {
+		if m.Store != nil {
+			s = m.readStore(c.Value)
+		} else if s, err = m.decrypt(c.Value); err != nil {
+			s = nil
+		}
 	}
-
-	s, err := m.decrypt(c.Value)
-	if err != nil {
+	if s == nil {
 		s = newSession()
-		ensureCSRF(s)
-		return s
 	}
 
 	if s.Values == nil {
@@ -147,7 +275,7 @@ func (m *SessionManager) load(r *http.Request) *Session {
 	if s.Flashes == nil {
 		s.Flashes = make(map[string]any)
 	}
-	ensureCSRF(s)
+	ensureTokens(s)
 	return s
 }
 
@@ -159,7 +287,15 @@ func (m *SessionManager) save(w http.ResponseWriter, s *Session) error {
 		return nil
 	}
 
-	enc, err := m.encrypt(s)
+	var (
+		enc string
+		err error
+	)
+	if m.Store != nil {
+		enc, err = m.writeStore(s)
+	} else {
+		enc, err = m.encrypt(s)
+	}
 	if err != nil {
 		return err
 	}
@@ -174,33 +310,83 @@ func (m *SessionManager) save(w http.ResponseWriter, s *Session) error {
 		SameSite: m.SameSite,
 		Expires:  time.Now().Add(m.MaxAge),
 	}
+	if size := len(cookie.String()); size > maxCookieSize {
+		return fmt.Errorf("session: cookie is %d bytes, over the %d byte limit; use a SessionStore", size, maxCookieSize)
+	}
 	http.SetCookie(w, cookie)
 	return nil
 }
 
-func (m *SessionManager) encrypt(s *Session) (string, error) {
-	payload, err := json.Marshal(s)
+// readStore loads the session whose encrypted ID is value, or returns nil.
+func (m *SessionManager) readStore(value string) *Session {
+	// s2 IDs are sealed with their own key derived from APP_KEY; s1 IDs, issued by
+	// earlier versions, with APP_KEY itself and are reissued as s2.
+	var (
+		id     []byte
+		err    error
+		legacy bool
+	)
+	switch {
+	case strings.HasPrefix(value, "s2."):
+		id, err = unseal(purposeKey(m.Key, purposeSessionID), strings.TrimPrefix(value, "s2."))
+	case strings.HasPrefix(value, "s1."):
+		id, err = unseal(m.Key, strings.TrimPrefix(value, "s1."))
+		legacy = true
+	default:
+		return nil
+	}
 	if err != nil {
-		return "", err
+		return nil
 	}
-
-	block, err := aes.NewCipher(m.Key)
+	s, err := m.Store.Read(string(id))
 	if err != nil {
+		log.Printf("http: session store read failed: %v", err)
+		return nil
+	}
+	if s == nil {
+		return nil
+	}
+	s.storeID = string(id)
+	s.dirty = legacy
+	return s
+}
+
+// writeStore saves s in the store, under a new ID after Regenerate, and returns
+// the cookie value.
+func (m *SessionManager) writeStore(s *Session) (string, error) {
+	if s.oldStoreID != "" {
+		if err := m.Store.Destroy(s.oldStoreID); err != nil {
+			return "", err
+		}
+		s.oldStoreID = ""
+	}
+	if s.storeID == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		s.storeID = base64.RawURLEncoding.EncodeToString(b)
+	}
+	if err := m.Store.Write(s.storeID, s); err != nil {
 		return "", err
 	}
-	gcm, err := cipher.NewGCM(block)
+	sealed, err := seal(purposeKey(m.Key, purposeSessionID), []byte(s.storeID))
 	if err != nil {
 		return "", err
 	}
+	return "s2." + sealed, nil
+}
 
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+func (m *SessionManager) encrypt(s *Session) (string, error) {
+	payload, err := m.codec().Marshal(s)
+	if err != nil {
 		return "", err
 	}
-
-	ciphertext := gcm.Seal(nil, nonce, payload, nil)
-	out := append(nonce, ciphertext...)
-	return "v1." + base64.RawURLEncoding.EncodeToString(out), nil
+	sealed, err := seal(purposeKey(m.Key, purposeSession), payload)
+	if err != nil {
+		return "", err
+	}
+	return "v2." + sealed, nil
 }
 
 func (m *SessionManager) decrypt(value string) (*Session, error) {
@@ -208,16 +394,81 @@ func (m *SessionManager) decrypt(value string) (*Session, error) {
 	if value == "" {
 		return nil, fmt.Errorf("session: empty")
 	}
-	if !strings.HasPrefix(value, "v1.") {
+
+	// v2 cookies are sealed with the session key derived from APP_KEY; v1 cookies,
+	// issued by earlier versions, with APP_KEY itself. They still open, so upgrading
+	// does not log everyone out, and are rewritten as v2 by this response.
+	var (
+		plain []byte
+		err   error
+	)
+	switch {
+	case strings.HasPrefix(value, "v2."):
+		plain, err = unseal(purposeKey(m.Key, purposeSession), strings.TrimPrefix(value, "v2."))
+	case strings.HasPrefix(value, "v1."):
+		plain, err = unseal(m.Key, strings.TrimPrefix(value, "v1."))
+	default:
 		return nil, fmt.Errorf("session: unsupported version")
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	var s Session
+	if err := m.codec().Unmarshal(plain, &s); err != nil {
+		return nil, err
+	}
+	s.dirty = strings.HasPrefix(value, "v1.")
+	return &s, nil
+}
+
+// Purposes of the keys derived from APP_KEY by purposeKey.
+const (
+	purposeSession   = "session"
+	purposeSessionID = "session-id"
+	purposeParams    = "params"
+	purposeCookie    = "cookie"
+)
+
+// purposeKey derives the AES key for one use of the application key, so a value
+// sealed for one purpose (say, an encrypted URL parameter) never opens as another
+// (a session cookie).
+func purposeKey(key []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("jimo:" + purpose))
+	return mac.Sum(nil)
+}
+
+// seal encrypts plain with AES-GCM and returns nonce+ciphertext as unpadded
+// URL-safe base64.
+func seal(key, plain []byte) (string, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	ciphertext := gcm.Seal(nil, nonce, plain, nil)
+	out := append(nonce, ciphertext...)
+	return base64.RawURLEncoding.EncodeToString(out), nil
+}
 
-	blob, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(value, "v1."))
+// unseal reverses seal, failing if the value was tampered with.
+func unseal(key []byte, value string) ([]byte, error) {
+	blob, err := base64.RawURLEncoding.DecodeString(value)
 	if err != nil {
 		return nil, err
 	}
 
-	block, err := aes.NewCipher(m.Key)
+	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
 	}
@@ -231,17 +482,22 @@ func (m *SessionManager) decrypt(value string) (*Session, error) {
 
 	nonce := blob[:gcm.NonceSize()]
 	ciphertext := blob[gcm.NonceSize():]
-	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
-	if err != nil {
-		return nil, err
-	}
+	return gcm.Open(nil, nonce, ciphertext, nil)
+}
 
-	var s Session
-	if err := json.Unmarshal(plain, &s); err != nil {
-		return nil, err
+func ensureTokens(s *Session) {
+	ensureID(s)
+	ensureCSRF(s)
+}
+
+func ensureID(s *Session) {
+	if s.SID != "" {
+		return
 	}
-	s.dirty = false
-	return &s, nil
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	s.SID = base64.RawURLEncoding.EncodeToString(b)
+	s.dirty = true
 }
 
 func ensureCSRF(s *Session) {
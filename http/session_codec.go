@@ -0,0 +1,52 @@
+package http
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"time"
+)
+
+// SessionCodec serializes sessions before they are encrypted into the cookie.
+type SessionCodec interface {
+	Marshal(s *Session) ([]byte, error)
+	Unmarshal(data []byte, s *Session) error
+}
+
+// JSONCodec is the default session codec.
+//
+// It keeps payloads readable when debugging, but values come back as JSON types
+// (numbers as float64, structs as map[string]any).
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(s *Session) ([]byte, error) { return json.Marshal(s) }
+
+func (JSONCodec) Unmarshal(data []byte, s *Session) error { return json.Unmarshal(data, s) }
+
+// GobCodec serializes sessions with encoding/gob, preserving Go types across requests.
+//
+// Custom types stored in the session must be registered with gob.Register;
+// common composite types (maps, slices, time.Time) are registered already.
+type GobCodec struct{}
+
+func (GobCodec) Marshal(s *Session) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Unmarshal(data []byte, s *Session) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(s)
+}
+
+func init() {
+	gob.Register(map[string]any{})
+	gob.Register(map[string]string{})
+	gob.Register([]any{})
+	gob.Register([]string{})
+	gob.Register([]int{})
+	gob.Register(time.Time{})
+	gob.Register(time.Duration(0))
+}
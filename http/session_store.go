@@ -0,0 +1,266 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SessionStore keeps sessions on the server for SessionManager.Store.
+//
+// IDs are random secrets generated by the SessionManager. Read returns nil and no
+// error for unknown or expired IDs. Write replaces the session stored under id.
+// Implementations must be safe for concurrent use.
+type SessionStore interface {
+	Read(id string) (*Session, error)
+	Write(id string, s *Session) error
+	Destroy(id string) error
+}
+
+// MemorySessionStore is an in-process SessionStore for a single server or tests.
+// Every session is gone once the process restarts.
+type MemorySessionStore struct {
+	// Codec serializes stored sessions; nil means JSONCodec.
+	Codec SessionCodec
+
+	ttl       time.Duration
+	mu        sync.Mutex
+	entries   map[string]memorySession
+	lastSweep time.Time
+}
+
+type memorySession struct {
+	data    []byte
+	expires time.Time // zero means never
+}
+
+// NewMemorySessionStore creates an empty in-memory store whose sessions expire
+// ttl after they were last read or written (0 means never), so sessions of
+// active users that only read them stay alive.
+func NewMemorySessionStore(ttl time.Duration) *MemorySessionStore {
+	return &MemorySessionStore{ttl: ttl, entries: make(map[string]memorySession)}
+}
+
+func (m *MemorySessionStore) Read(id string) (*Session, error) {
+	m.mu.Lock()
+	e, ok := m.entries[id]
+	if ok && !e.expires.IsZero() {
+		if now := time.Now(); now.After(e.expires) {
+			delete(m.entries, id)
+			ok = false
+		} else {
+			e.expires = now.Add(m.ttl)
+			m.entries[id] = e
+		}
+	}
+	m.mu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	var s Session
+	if err := sessionCodec(m.Codec).Unmarshal(e.data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (m *MemorySessionStore) Write(id string, s *Session) error {
+	data, err := sessionCodec(m.Codec).Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	e := memorySession{data: data}
+	if m.ttl > 0 {
+		e.expires = now.Add(m.ttl)
+	}
+	m.entries[id] = e
+
+	// Drop expired sessions at most once a minute.
+	if now.Sub(m.lastSweep) >= time.Minute {
+		m.lastSweep = now
+		for k, e := range m.entries {
+			if !e.expires.IsZero() && now.After(e.expires) {
+				delete(m.entries, k)
+			}
+		}
+	}
+	return nil
+}
+
+func (m *MemorySessionStore) Destroy(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, id)
+	return nil
+}
+
+// FileSessionStore is a SessionStore keeping one file per session in a
+// directory, created with mode 0700 on the first write.
+//
+// Expired files are ignored when read; call Prune periodically (e.g. from a
+// scheduled task) to delete them.
+type FileSessionStore struct {
+	// Codec serializes stored sessions; nil means JSONCodec.
+	Codec SessionCodec
+
+	dir string
+	ttl time.Duration
+}
+
+// NewFileSessionStore creates a store in dir whose sessions expire ttl after
+// they were last read or written (0 means never). Reads refresh the file's
+// modification time.
+func NewFileSessionStore(dir string, ttl time.Duration) *FileSessionStore {
+	return &FileSessionStore{dir: dir, ttl: ttl}
+}
+
+func (f *FileSessionStore) Read(id string) (*Session, error) {
+	path, err := f.path(id)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if f.expired(info, now) {
+		_ = os.Remove(path)
+		return nil, nil
+	}
+	if f.ttl > 0 {
+		_ = os.Chtimes(path, now, now)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s Session
+	if err := sessionCodec(f.Codec).Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (f *FileSessionStore) Write(id string, s *Session) error {
+	path, err := f.path(id)
+	if err != nil {
+		return err
+	}
+	data, err := sessionCodec(f.Codec).Marshal(s)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(f.dir, 0o700); err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it, so readers never see a partial session.
+	tmp, err := os.CreateTemp(f.dir, ".session-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+func (f *FileSessionStore) Destroy(id string) error {
+	path, err := f.path(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Prune deletes expired session files.
+func (f *FileSessionStore) Prune() error {
+	if f.ttl <= 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(f.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, e := range entries {
+		if e.IsDir() || !validSessionID(e.Name()) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if f.expired(info, now) {
+			if err := os.Remove(filepath.Join(f.dir, e.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (f *FileSessionStore) expired(info os.FileInfo, now time.Time) bool {
+	return f.ttl > 0 && now.After(info.ModTime().Add(f.ttl))
+}
+
+func (f *FileSessionStore) path(id string) (string, error) {
+	if !validSessionID(id) {
+		return "", fmt.Errorf("session: invalid session id")
+	}
+	return filepath.Join(f.dir, id), nil
+}
+
+// validSessionID reports whether id is an unpadded URL-safe base64 string, the
+// form SessionManager generates, so it is safe to use as a file name.
+func validSessionID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+func sessionCodec(c SessionCodec) SessionCodec {
+	if c == nil {
+		return JSONCodec{}
+	}
+	return c
+}
@@ -0,0 +1,149 @@
+package http
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sessionRoundTrip stores a 16KB value on one request and reads it back on the next.
+func sessionRoundTrip(t *testing.T, store SessionStore) (setCookie []*http.Cookie, readBack string) {
+	t.Helper()
+	sm, err := NewSessionManager("test-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm.Store = store
+
+	big := strings.Repeat("x", 16<<10)
+	r := NewRouter()
+	r.Use(Sessions(sm))
+	r.Get("/put", func(c *Context) {
+		c.Session().Put("cart", big)
+		c.String(http.StatusOK, "ok")
+	})
+	r.Get("/get", func(c *Context) {
+		v, _ := c.Session().GetString("cart")
+		c.String(http.StatusOK, v)
+	})
+
+	rec := serve(r, http.MethodGet, "/put")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("put status = %d", rec.Code)
+	}
+	cookies := rec.Result().Cookies()
+	req := httptest.NewRequest(http.MethodGet, "/get", nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return cookies, rec.Body.String()
+}
+
+func TestLargeSessionWithStore(t *testing.T) {
+	for name, store := range map[string]SessionStore{
+		"memory": NewMemorySessionStore(time.Hour),
+		"file":   NewFileSessionStore(t.TempDir(), time.Hour),
+	} {
+		t.Run(name, func(t *testing.T) {
+			cookies, got := sessionRoundTrip(t, store)
+			if len(cookies) != 1 || len(cookies[0].Value) > 200 {
+				t.Fatalf("cookie should only carry the session id: %d cookies", len(cookies))
+			}
+			if len(got) != 16<<10 {
+				t.Fatalf("read back %d bytes, want %d", len(got), 16<<10)
+			}
+		})
+	}
+}
+
+func TestLargeSessionCookieOnlyFailsGracefully(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	cookies, got := sessionRoundTrip(t, nil)
+	if len(cookies) != 0 {
+		t.Fatalf("an oversized session cookie was set (%d bytes)", len(cookies[0].Value))
+	}
+	if got != "" {
+		t.Fatal("oversized session value was read back")
+	}
+	if !strings.Contains(logs.String(), "use a SessionStore") {
+		t.Fatalf("missing log hint, got %q", logs.String())
+	}
+}
+
+func TestMemorySessionStoreReadRefreshesTTL(t *testing.T) {
+	m := NewMemorySessionStore(time.Hour)
+	if err := m.Write("abc", newSession()); err != nil {
+		t.Fatal(err)
+	}
+	m.entries["abc"] = memorySession{data: m.entries["abc"].data, expires: time.Now().Add(time.Second)}
+
+	if s, err := m.Read("abc"); err != nil || s == nil {
+		t.Fatalf("Read = %v, %v", s, err)
+	}
+	if left := time.Until(m.entries["abc"].expires); left < 59*time.Minute {
+		t.Fatalf("Read did not refresh the TTL: %s left", left)
+	}
+
+	m.entries["abc"] = memorySession{data: m.entries["abc"].data, expires: time.Now().Add(-time.Second)}
+	if s, _ := m.Read("abc"); s != nil {
+		t.Fatal("expired session was returned")
+	}
+}
+
+func TestFileSessionStoreReadRefreshesTTL(t *testing.T) {
+	dir := t.TempDir()
+	f := NewFileSessionStore(dir, time.Hour)
+	if err := f.Write("abc", newSession()); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "abc")
+
+	old := time.Now().Add(-59 * time.Minute)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := f.Read("abc"); err != nil || s == nil {
+		t.Fatalf("Read = %v, %v", s, err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(info.ModTime()) > time.Minute {
+		t.Fatalf("Read did not refresh the modification time: %s", info.ModTime())
+	}
+
+	expired := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, expired, expired); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := f.Read("abc"); s != nil {
+		t.Fatal("expired session was returned")
+	}
+	if err := f.Prune(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatal("expired session file was not removed")
+	}
+}
+
+func TestFileSessionStoreRejectsPathIDs(t *testing.T) {
+	f := NewFileSessionStore(t.TempDir(), 0)
+	for _, id := range []string{"../etc/passwd", "a/b", ""} {
+		if err := f.Write(id, newSession()); err == nil {
+			t.Errorf("Write(%q) succeeded", id)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func sessionCookieRouter(t *testing.T) (*Router, *SessionManager) {
+	t.Helper()
+	sm, err := NewSessionManager("test-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRouter()
+	r.Use(Sessions(sm))
+	r.Get("/", func(c *Context) {
+		v, _ := c.Session().GetString("user")
+		c.String(http.StatusOK, v)
+	})
+	return r, sm
+}
+
+func TestSessionCookiesAreV2(t *testing.T) {
+	_, sm := sessionCookieRouter(t)
+	s := newSession()
+	s.Put("user", "ada")
+	enc, err := sm.encrypt(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(enc, "v2.") {
+		t.Fatalf("cookie %q lacks the v2 prefix", enc)
+	}
+	if _, err := unseal(sm.Key, strings.TrimPrefix(enc, "v2.")); err == nil {
+		t.Error("v2 cookie opens with the raw APP_KEY")
+	}
+}
+
+func TestLegacyV1SessionCookieStillOpens(t *testing.T) {
+	r, sm := sessionCookieRouter(t)
+
+	// Cookies issued before the session key was derived were sealed with APP_KEY.
+	s := newSession()
+	s.Put("user", "ada")
+	payload, err := sm.codec().Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := seal(sm.Key, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: sm.CookieName, Value: "v1." + sealed})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "ada" {
+		t.Fatalf("v1 session lost: got %q", rec.Body.String())
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || !strings.HasPrefix(cookies[0].Value, "v2.") {
+		t.Fatalf("v1 cookie not reissued as v2: %v", cookies)
+	}
+}
+
+func TestSessionCookieWithUnknownVersionIsIgnored(t *testing.T) {
+	r, sm := sessionCookieRouter(t)
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: sm.CookieName, Value: "v9.abc"})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Body.String() != "" {
+		t.Fatalf("got %d %q", rec.Code, rec.Body.String())
+	}
+}
+
+func TestLegacyS1StoreIDStillOpens(t *testing.T) {
+	r, sm := sessionCookieRouter(t)
+	sm.Store = NewMemorySessionStore(0)
+
+	s := newSession()
+	s.Put("user", "ada")
+	if err := sm.Store.Write("legacy-id", s); err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := seal(sm.Key, []byte("legacy-id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: sm.CookieName, Value: "s1." + sealed})
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	if rec.Body.String() != "ada" {
+		t.Fatalf("s1 session lost: got %q", rec.Body.String())
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || !strings.HasPrefix(cookies[0].Value, "s2.") {
+		t.Fatalf("s1 id not reissued as s2: %v", cookies)
+	}
+}
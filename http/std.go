@@ -0,0 +1,69 @@
+package http
+
+import "net/http"
+
+// FromStd adapts a net/http handler to a HandlerFunc, so existing or third-party
+// handlers can serve a single route:
+//
+//	r.Get("/metrics", FromStd(promhttp.Handler()))
+//	r.Get("/files/{name}", FromStd(http.HandlerFunc(legacyDownload)))
+//
+// The handler writes to the Context's ResponseWriter, so middleware wrapping the
+// writer keeps working. Route params are exposed as path values (Request.PathValue);
+// the session and other Context state stay available to the surrounding
+// middleware but not to h.
+func FromStd(h http.Handler) HandlerFunc {
+	if h == nil {
+		panic("http: FromStd handler is nil")
+	}
+	return func(ctx *Context) {
+		p := ctx.params
+		for i, name := range p.names {
+			ctx.Request.SetPathValue(name, p.values[i])
+		}
+		h.ServeHTTP(ctx.ResponseWriter, ctx.Request)
+	}
+}
+
+// StdMiddleware adapts framework middleware to a net/http middleware, so it can
+// wrap handlers in an existing net/http, chi or gorilla/mux chain:
+//
+//	mux.Handle("/admin/", StdMiddleware(Sessions(sm))(adminHandler))
+//
+// Each request gets a Context without a router: route params are empty, views
+// are unavailable and router settings (error handler, JSON envelope) do not
+// apply. next receives the Context's ResponseWriter and Request, including any
+// the middleware swapped in, and is skipped when the middleware halts or does
+// not call it. HTTPError panics are answered like DefaultErrorHandler; other
+// panics propagate to the surrounding chain.
+func StdMiddleware(mw Middleware) func(http.Handler) http.Handler {
+	if mw == nil {
+		panic("http: StdMiddleware middleware is nil")
+	}
+	return func(next http.Handler) http.Handler {
+		h := mw(skipHalted(func(ctx *Context) {
+			next.ServeHTTP(ctx.ResponseWriter, ctx.Request)
+		}))
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ctx := NewContext(w, req, nil)
+			defer ctx.runDeferred()
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				switch rec.(type) {
+				case HTTPError, *HTTPError:
+					if ctx.IsClientGone() {
+						return
+					}
+					ctx.ResponseWriter = w
+					DefaultErrorHandler(ctx, rec)
+				default:
+					panic(rec)
+				}
+			}()
+			h(ctx)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+// streamFlushEvery bounds how many items JSONStream buffers between flushes.
+const streamFlushEvery = 64
+
+// JSONStream writes the items received from items as a JSON array, encoding and
+// sending them as they arrive instead of building the whole response in memory,
+// e.g. to stream database rows:
+//
+//	rows := make(chan any)
+//	go func() {
+//		defer close(rows)
+//		for _, p := range posts { // or a cursor over the result set
+//			select {
+//			case rows <- p:
+//			case <-ctx.Request.Context().Done():
+//				return
+//			}
+//		}
+//	}()
+//	ctx.JSONStream(http.StatusOK, rows)
+//
+// The array is closed once items is closed. Output is flushed whenever the
+// producer has nothing ready and at least every 64 items. If the request is
+// canceled (client gone or deadline exceeded) it returns at once, so producers
+// should also watch the request context rather than block on send.
+//
+// The WrapJSON envelope is not applied. Since the status is sent first, an item
+// that fails to encode cannot become an error response: the failure is logged
+// and the array is left unterminated, so the client sees invalid JSON rather
+// than a silently truncated list.
+func (c *Context) JSONStream(status int, items <-chan any) {
+	w := c.ResponseWriter
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	rc := http.NewResponseController(w)
+	done := c.Request.Context().Done()
+
+	write := func(p []byte) bool {
+		if _, err := w.Write(p); err != nil {
+			c.wroteErr(err)
+			return false
+		}
+		return true
+	}
+	if !write([]byte{'['}) {
+		return
+	}
+
+	pending := 0
+	for n := 0; ; n++ {
+		var (
+			item any
+			ok   bool
+		)
+		select {
+		case item, ok = <-items:
+		default:
+			if pending > 0 {
+				_ = rc.Flush()
+				pending = 0
+			}
+			select {
+			case item, ok = <-items:
+			case <-done:
+				return
+			}
+		}
+		if !ok {
+			if write([]byte("]\n")) {
+				_ = rc.Flush()
+			}
+			return
+		}
+		select {
+		case <-done:
+			return
+		default:
+		}
+
+		b, err := json.Marshal(item)
+		if err != nil {
+			log.Printf("http: JSONStream item %d: %v", n, err)
+			return
+		}
+		if n > 0 && !write([]byte{','}) {
+			return
+		}
+		if !write(b) {
+			return
+		}
+		if pending++; pending >= streamFlushEvery {
+			_ = rc.Flush()
+			pending = 0
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+)
+
+func slashRouter(redirect bool) *Router {
+	r := NewRouter()
+	r.RedirectTrailingSlash(redirect)
+	ok := func(c *Context) { c.String(http.StatusOK, "ok") }
+	r.Get("/users", ok)
+	r.Post("/users", ok)
+	r.Get("/", ok)
+	return r
+}
+
+func TestRedirectTrailingSlash(t *testing.T) {
+	r := slashRouter(true)
+	for _, tc := range []struct {
+		method, path string
+		status       int
+		location     string
+	}{
+		{http.MethodGet, "/users/", http.StatusMovedPermanently, "/users"},
+		{http.MethodHead, "/users/", http.StatusMovedPermanently, "/users"},
+		{http.MethodGet, "/users/?page=2", http.StatusMovedPermanently, "/users?page=2"},
+		{http.MethodPost, "/users/", http.StatusPermanentRedirect, "/users"},
+		{http.MethodGet, "/users", http.StatusOK, ""},
+		{http.MethodGet, "/", http.StatusOK, ""},
+		{http.MethodGet, "/missing/", http.StatusNotFound, ""},
+		{http.MethodGet, "//users/", http.StatusMovedPermanently, "/users"},
+	} {
+		rec := serve(r, tc.method, tc.path)
+		if rec.Code != tc.status || rec.Header().Get("Location") != tc.location {
+			t.Errorf("%s %s = %d %q, want %d %q", tc.method, tc.path, rec.Code, rec.Header().Get("Location"), tc.status, tc.location)
+		}
+	}
+}
+
+func TestTrailingSlashServedWhenRedirectOff(t *testing.T) {
+	r := slashRouter(false)
+	for _, path := range []string{"/users", "/users/"} {
+		if rec := serve(r, http.MethodGet, path); rec.Code != http.StatusOK {
+			t.Errorf("GET %s = %d, want 200", path, rec.Code)
+		}
+	}
+}
@@ -4,67 +4,234 @@ import (
 	"fmt"
 	"html/template"
 	"io"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/jimo-go/framework/features"
 )
 
+// Renderer renders a named template, such as the router's view engine.
+type Renderer interface {
+	Render(w io.Writer, name string, data any) error
+}
+
+// ViewError is a template that failed to load, parse or execute. Name is the view
+// as requested ("users/show") and Path the file it resolved to.
+type ViewError struct {
+	Name string
+	Path string
+	Err  error
+}
+
+func (e *ViewError) Error() string {
+	if e.Path == "" {
+		if e.Name == "" {
+			return "view: " + e.Err.Error()
+		}
+		return "view: " + e.Name + ": " + e.Err.Error()
+	}
+	return "view: " + e.Path + ": " + e.Err.Error()
+}
+
+func (e *ViewError) Unwrap() error { return e.Err }
+
 type viewEngine struct {
 	dir   string
 	mu    sync.RWMutex
-	cache map[string]*template.Template
+	cache map[string]cachedView
+	funcs template.FuncMap
+	watch bool
+}
+
+type cachedView struct {
+	tpl      *template.Template
+	files    []string // the view and the layouts it extends
+	modTimes []time.Time
+}
+
+// fresh reports whether none of the view's files changed since it was parsed.
+func (c cachedView) fresh() bool {
+	for i, f := range c.files {
+		info, err := os.Stat(f)
+		if err != nil || !info.ModTime().Equal(c.modTimes[i]) {
+			return false
+		}
+	}
+	return true
 }
 
+// extendsDirective is the comment a view starts with to inherit from a layout:
+//
+//	{{/* extends "layouts/base" */}}
+var extendsDirective = regexp.MustCompile(`^\s*\{\{-?\s*/\*\s*extends\s+"([^"]+)"\s*\*/\s*-?\}\}`)
+
+// maxExtendsDepth bounds layout chains, which also catches cycles.
+const maxExtendsDepth = 16
+
 func newViewEngine(dir string) *viewEngine {
-	return &viewEngine{dir: dir, cache: make(map[string]*template.Template)}
+	return &viewEngine{
+		dir:   dir,
+		cache: make(map[string]cachedView),
+		funcs: template.FuncMap{
+			"feature": features.IsEnabled,
+		},
+	}
 }
 
 func (v *viewEngine) SetDir(dir string) {
 	v.mu.Lock()
 	defer v.mu.Unlock()
 	v.dir = dir
-	v.cache = make(map[string]*template.Template)
+	v.cache = make(map[string]cachedView)
+}
+
+// SetWatch enables re-parsing cached templates whose file modification time changed.
+//
+// It costs one stat per render and suits deployments that replace templates
+// without restarting the process.
+func (v *viewEngine) SetWatch(watch bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.watch = watch
 }
 
 func (v *viewEngine) Render(w io.Writer, name string, data any) error {
-	tpl, err := v.template(name)
+	tpl, path, err := v.template(name)
 	if err != nil {
 		return err
 	}
-	return tpl.Execute(w, data)
+	if err := tpl.Execute(w, data); err != nil {
+		return &ViewError{Name: name, Path: path, Err: err}
+	}
+	return nil
 }
 
-func (v *viewEngine) template(name string) (*template.Template, error) {
-	name = strings.TrimSpace(name)
-	if name == "" {
-		return nil, fmt.Errorf("view: template name is empty")
+// RenderBlock renders a single named template ({{define}} or {{block}}) from the
+// named view file.
+func (v *viewEngine) RenderBlock(w io.Writer, name, block string, data any) error {
+	tpl, path, err := v.template(name)
+	if err != nil {
+		return err
 	}
-	if strings.Contains(name, "..") {
-		return nil, fmt.Errorf("view: invalid template name")
+	if tpl.Lookup(block) == nil {
+		return &ViewError{Name: name, Path: path, Err: fmt.Errorf("no block %q", block)}
 	}
-	if filepath.Ext(name) == "" {
-		name += ".html"
+	if err := tpl.ExecuteTemplate(w, block, data); err != nil {
+		return &ViewError{Name: name, Path: path, Err: err}
+	}
+	return nil
+}
+
+// template returns the parsed view and the file path it was loaded from.
+//
+// A view starting with {{/* extends "layout" */}} is parsed together with its
+// layout (and the layout's own layout, and so on): the outermost layout is
+// executed, and {{define}}s in the view override the layout's {{block}}s. The
+// combined template is cached under the view's name; with SetWatch a change to
+// any file of the chain re-parses it.
+func (v *viewEngine) template(name string) (*template.Template, string, error) {
+	requested := name
+	name, err := viewFile(name)
+	if err != nil {
+		return nil, "", &ViewError{Name: requested, Err: err}
 	}
 
 	v.mu.RLock()
-	tpl := v.cache[name]
+	cached, ok := v.cache[name]
 	dir := v.dir
+	watch := v.watch
 	v.mu.RUnlock()
-	if tpl != nil {
-		return tpl, nil
-	}
 
 	path := filepath.Join(dir, name)
-	parsed, err := template.ParseFiles(path)
+	if ok && (!watch || cached.fresh()) {
+		return cached.tpl, path, nil
+	}
+
+	parsed, err := v.parse(dir, name)
 	if err != nil {
-		return nil, err
+		if ve, ok := err.(*ViewError); ok {
+			ve.Name = requested
+			return nil, path, ve
+		}
+		return nil, path, &ViewError{Name: requested, Path: path, Err: err}
 	}
 
 	v.mu.Lock()
 	defer v.mu.Unlock()
-	if existing := v.cache[name]; existing != nil {
-		return existing, nil
-	}
 	v.cache[name] = parsed
-	return parsed, nil
+	return parsed.tpl, path, nil
+}
+
+// viewFile normalizes a view name to a file name relative to the views directory.
+func viewFile(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("template name is empty")
+	}
+	if strings.Contains(name, "..") {
+		return "", fmt.Errorf("invalid template name")
+	}
+	if filepath.Ext(name) == "" {
+		name += ".html"
+	}
+	return name, nil
+}
+
+// parse reads the view and the layouts it extends and parses them into one
+// template set rooted at the outermost layout.
+func (v *viewEngine) parse(dir, name string) (cachedView, error) {
+	type source struct {
+		name, path, text string
+	}
+	var (
+		chain []source
+		out   cachedView
+	)
+	for {
+		if len(chain) == maxExtendsDepth {
+			return cachedView{}, fmt.Errorf("layouts nested too deeply (cycle?)")
+		}
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			return cachedView{}, &ViewError{Path: path, Err: err}
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return cachedView{}, &ViewError{Path: path, Err: err}
+		}
+		text := string(b)
+		chain = append(chain, source{name: name, path: path, text: text})
+		out.files = append(out.files, path)
+		out.modTimes = append(out.modTimes, info.ModTime())
+
+		m := extendsDirective.FindStringSubmatch(text)
+		if m == nil {
+			break
+		}
+		if name, err = viewFile(m[1]); err != nil {
+			return cachedView{}, &ViewError{Path: path, Err: fmt.Errorf("extends %q: %w", m[1], err)}
+		}
+	}
+
+	// Parse from the outermost layout in, so each view's {{define}}s replace the
+	// {{block}} defaults of the layouts around it.
+	root := chain[len(chain)-1]
+	tpl := template.New(root.name).Funcs(v.funcs)
+	for i := len(chain) - 1; i >= 0; i-- {
+		src := chain[i]
+		t := tpl
+		if i != len(chain)-1 {
+			t = tpl.New(src.name)
+		}
+		if _, err := t.Parse(src.text); err != nil {
+			return cachedView{}, &ViewError{Path: src.path, Err: err}
+		}
+	}
+	out.tpl = tpl
+	return out, nil
 }
@@ -1,11 +1,16 @@
 package http
 
 import (
+	"log"
 	"net/http"
 	"strings"
 )
 
 // Sessions loads and saves a cookie-backed session for each request.
+//
+// The cookie is saved just before the response headers are written, so handlers
+// that write a response (including redirects) keep their session changes. Changes
+// made after the headers were sent cannot be saved.
 func Sessions(sm *SessionManager) Middleware {
 	return func(next HandlerFunc) HandlerFunc {
 		return func(ctx *Context) {
@@ -15,14 +20,61 @@ func Sessions(sm *SessionManager) Middleware {
 			}
 			s := sm.load(ctx.Request)
 			ctx.session = s
+			ctx.sessions = sm
+			sw := &sessionWriter{ResponseWriter: ctx.ResponseWriter, sm: sm, session: s}
+			ctx.ResponseWriter = sw
 			defer func() {
-				_ = sm.save(ctx.ResponseWriter, s)
+				if ctx.ResponseWriter == sw {
+					ctx.ResponseWriter = sw.ResponseWriter
+				}
+				sw.save()
 			}()
 			next(ctx)
 		}
 	}
 }
 
+// sessionWriter saves the session cookie before the first header write.
+type sessionWriter struct {
+	http.ResponseWriter
+	sm      *SessionManager
+	session *Session
+	saved   bool
+}
+
+func (w *sessionWriter) save() {
+	if w.saved {
+		return
+	}
+	w.saved = true
+	if err := w.sm.save(w.ResponseWriter, w.session); err != nil {
+		log.Printf("http: session not saved: %v", err)
+	}
+}
+
+func (w *sessionWriter) WriteHeader(status int) {
+	w.save()
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *sessionWriter) Write(p []byte) (int, error) {
+	w.save()
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher when the underlying writer does.
+func (w *sessionWriter) Flush() {
+	w.save()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *sessionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 // CSRF protects unsafe methods using the token stored in session.
 //
 // It expects the token in one of:
@@ -0,0 +1,41 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+)
+
+func envelopeRouter(wrapErrors bool) *Router {
+	r := NewRouter()
+	r.WrapJSON(func(status int, data any) any {
+		return Map{"data": data, "meta": Map{"status": status}}
+	})
+	r.WrapJSONErrors(wrapErrors)
+	r.Get("/wrapped", func(c *Context) { c.JSON(http.StatusOK, Map{"id": 1}) })
+	r.Get("/raw", func(c *Context) { c.JSONRaw(http.StatusOK, Map{"id": 1}) })
+	r.Get("/fail", func(c *Context) { panic(HTTPError{Status: http.StatusConflict, Message: "Taken"}) })
+	return r
+}
+
+func TestWrapJSON(t *testing.T) {
+	r := envelopeRouter(false)
+	for path, want := range map[string]string{
+		"/wrapped": `{"data":{"id":1},"meta":{"status":200}}` + "\n",
+		"/raw":     `{"id":1}` + "\n",
+		"/fail":    `{"message":"Taken"}` + "\n",
+	} {
+		if rec := serve(r, http.MethodGet, path); rec.Body.String() != want {
+			t.Errorf("GET %s = %q, want %q", path, rec.Body.String(), want)
+		}
+	}
+}
+
+func TestWrapJSONErrors(t *testing.T) {
+	rec := serve(envelopeRouter(true), http.MethodGet, "/fail")
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", rec.Code)
+	}
+	if want := `{"data":{"message":"Taken"},"meta":{"status":409}}` + "\n"; rec.Body.String() != want {
+		t.Fatalf("body = %q, want %q", rec.Body.String(), want)
+	}
+}
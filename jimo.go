@@ -23,9 +23,21 @@ type HandlerFunc = jimohttp.HandlerFunc
 // Middleware is a router middleware.
 type Middleware = jimohttp.Middleware
 
+// Map is a shorthand for building JSON objects in handlers.
+type Map = jimohttp.Map
+
 // RouteOption configures per-route behavior.
 type RouteOption = jimohttp.RouteOption
 
+// Route describes a route for App.Add.
+type Route = jimohttp.Route
+
+// GroupOption configures a route group.
+type GroupOption = jimohttp.GroupOption
+
+// PanicError is a recovered panic with its stack trace.
+type PanicError = core.PanicError
+
 // New creates a new Jimo application instance.
 func New() *App {
 	return core.New()
@@ -34,5 +46,30 @@ func New() *App {
 // Named assigns a name to a route.
 func Named(name string) RouteOption { return jimohttp.Named(name) }
 
+// GroupName prefixes the names of routes registered in a group.
+func GroupName(prefix string) GroupOption { return jimohttp.GroupName(prefix) }
+
+// Summary describes a route in the generated OpenAPI document.
+func Summary(text string) RouteOption { return jimohttp.Summary(text) }
+
+// Tags groups a route under tags in the generated OpenAPI document.
+func Tags(tags ...string) RouteOption { return jimohttp.Tags(tags...) }
+
+// Accepts records a route's JSON request body type for the OpenAPI document.
+func Accepts(v any) RouteOption { return jimohttp.Accepts(v) }
+
+// Returns records a route's JSON response body type for a status (nil: no body).
+func Returns(status int, v any) RouteOption { return jimohttp.Returns(status, v) }
+
 // WithMiddleware attaches middleware to a single route.
 func WithMiddleware(mw ...Middleware) RouteOption { return jimohttp.WithMiddleware(mw...) }
+
+// ReportServerErrors makes App.OnError also report 5xx HTTPErrors.
+func ReportServerErrors() jimohttp.ErrorReportOption { return jimohttp.ReportServerErrors() }
+
+// Scope returns the service container scope of the current request.
+func Scope(ctx *Context) *core.Container { return core.RequestScope(ctx) }
+
+// Go runs fn in a goroutine whose panics are recovered and reported instead of
+// crashing the process (see core.SafeGo).
+func Go(fn func()) { core.SafeGo(fn) }
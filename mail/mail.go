@@ -0,0 +1,132 @@
+// Package mail sends email messages through a pluggable Mailer.
+package mail
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+// Message is an email message.
+//
+// When both Text and HTML are set, the message is sent as multipart/alternative.
+type Message struct {
+	From    string
+	To      []string
+	Cc      []string
+	Bcc     []string
+	ReplyTo string
+	Subject string
+
+	Text string
+	HTML string
+
+	// Template and Data, when Template is set, render the HTML body at send time
+	// (see View).
+	Template string
+	Data     any
+}
+
+// Recipients returns every envelope recipient (To, Cc and Bcc).
+func (m Message) Recipients() []string {
+	out := make([]string, 0, len(m.To)+len(m.Cc)+len(m.Bcc))
+	out = append(out, m.To...)
+	out = append(out, m.Cc...)
+	out = append(out, m.Bcc...)
+	return out
+}
+
+func (m Message) validate() error {
+	if len(m.Recipients()) == 0 {
+		return errors.New("mail: message has no recipients")
+	}
+	if m.Text == "" && m.HTML == "" {
+		return errors.New("mail: message has no body")
+	}
+	for _, v := range append([]string{m.From, m.ReplyTo, m.Subject}, m.Recipients()...) {
+		if strings.ContainsAny(v, "\r\n") {
+			return errors.New("mail: header values must not contain line breaks")
+		}
+	}
+	return nil
+}
+
+// Mailer delivers messages.
+type Mailer interface {
+	Send(msg Message) error
+}
+
+var (
+	defaultMailer Mailer
+	defaultMu     sync.RWMutex
+)
+
+// Use sets the default mailer used by Send.
+func Use(m Mailer) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultMailer = m
+}
+
+// Default returns the currently configured default mailer.
+func Default() Mailer {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultMailer
+}
+
+// Send delivers msg through the default mailer.
+func Send(msg Message) error {
+	m := Default()
+	if m == nil {
+		return errors.New("mail: no mailer configured")
+	}
+	return m.Send(msg)
+}
+
+// LogMailer writes messages to W instead of delivering them (os.Stdout if nil).
+//
+// It is meant for local development.
+type LogMailer struct {
+	W io.Writer
+
+	// Views renders template messages (see View), e.g. app.Router.Views().
+	Views jimohttp.Renderer
+}
+
+func (l LogMailer) Send(msg Message) error {
+	if err := msg.render(l.Views); err != nil {
+		return err
+	}
+	if err := msg.validate(); err != nil {
+		return err
+	}
+	w := l.W
+	if w == nil {
+		w = os.Stdout
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\nTo: %s\n", msg.From, strings.Join(msg.To, ", "))
+	if len(msg.Cc) > 0 {
+		fmt.Fprintf(&b, "Cc: %s\n", strings.Join(msg.Cc, ", "))
+	}
+	if len(msg.Bcc) > 0 {
+		fmt.Fprintf(&b, "Bcc: %s\n", strings.Join(msg.Bcc, ", "))
+	}
+	fmt.Fprintf(&b, "Subject: %s\n\n", msg.Subject)
+	if msg.Text != "" {
+		b.WriteString(msg.Text)
+	} else {
+		b.WriteString(msg.HTML)
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
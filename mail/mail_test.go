@@ -0,0 +1,103 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSendRejectsHeaderInjection(t *testing.T) {
+	base := Message{From: "app@example.com", To: []string{"a@example.com"}, Subject: "Hi", Text: "body"}
+	cases := map[string]func(*Message){
+		"from":     func(m *Message) { m.From = "app@example.com\r\nBcc: victim@example.com" },
+		"to":       func(m *Message) { m.To = []string{"a@example.com\nBcc: victim@example.com"} },
+		"cc":       func(m *Message) { m.Cc = []string{"c@example.com\r\nX-Evil: 1"} },
+		"reply-to": func(m *Message) { m.ReplyTo = "r@example.com\r\nBcc: victim@example.com" },
+		"subject":  func(m *Message) { m.Subject = "Hi\r\nBcc: victim@example.com" },
+	}
+	for name, mutate := range cases {
+		t.Run(name, func(t *testing.T) {
+			msg := base
+			mutate(&msg)
+			var out bytes.Buffer
+			err := LogMailer{W: &out}.Send(msg)
+			if err == nil || !strings.Contains(err.Error(), "line breaks") {
+				t.Fatalf("Send error = %v, want line break rejection", err)
+			}
+			if out.Len() != 0 {
+				t.Fatalf("message was written: %q", out.String())
+			}
+		})
+	}
+}
+
+func TestBuildMIMEHeaders(t *testing.T) {
+	msg := Message{
+		From:    "app@example.com",
+		To:      []string{"a@example.com", "b@example.com"},
+		ReplyTo: "support@example.com",
+		Subject: "Grüße",
+		Text:    "plain",
+		HTML:    "<p>html</p>",
+	}
+	raw, err := buildMIME(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := string(raw)
+	for _, want := range []string{
+		"From: app@example.com\r\n",
+		"To: a@example.com, b@example.com\r\n",
+		"Reply-To: support@example.com\r\n",
+		"Subject: =?utf-8?q?Gr=C3=BC=C3=9Fe?=\r\n",
+		"multipart/alternative",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("MIME message missing %q:\n%s", want, s)
+		}
+	}
+}
+
+type stubViews map[string]string
+
+func (v stubViews) Render(w io.Writer, name string, data any) error {
+	tpl, ok := v[name]
+	if !ok {
+		return fmt.Errorf("view %s not found", name)
+	}
+	_, err := fmt.Fprintf(w, tpl, data)
+	return err
+}
+
+func TestViewRendersWithMailerViews(t *testing.T) {
+	views := stubViews{"emails/welcome": "<p>Hello <b>%v</b></p>"}
+	msg := View("emails/welcome", "Ada")
+	msg.To = []string{"ada@example.com"}
+
+	var out bytes.Buffer
+	if err := (LogMailer{W: &out, Views: views}).Send(msg); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "Hello Ada") {
+		t.Fatalf("rendered body missing: %q", out.String())
+	}
+}
+
+func TestViewWithoutRenderer(t *testing.T) {
+	msg := View("emails/welcome", nil)
+	msg.To = []string{"ada@example.com"}
+	err := LogMailer{W: io.Discard}.Send(msg)
+	if err == nil || !strings.Contains(err.Error(), "no view renderer") {
+		t.Fatalf("Send error = %v, want missing renderer", err)
+	}
+}
+
+func TestHTMLToText(t *testing.T) {
+	got := HTMLToText(`<html><head><title>x</title></head><body><p>Hi &amp; welcome</p><ul><li>One</li><li><a href="https://example.com">Two</a></li></ul></body></html>`)
+	want := "Hi & welcome\n\n- One\n- Two (https://example.com)"
+	if got != want {
+		t.Fatalf("HTMLToText = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,134 @@
+package mail
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+// SMTPMailer delivers messages through an SMTP server.
+//
+// Authentication (PLAIN) is used when Username is set; net/smtp only sends
+// credentials over TLS or to localhost.
+type SMTPMailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+
+	// From is used when a message has no From address.
+	From string
+
+	// Views renders template messages (see View), e.g. app.Router.Views().
+	Views jimohttp.Renderer
+}
+
+func (s SMTPMailer) Send(msg Message) error {
+	if msg.From == "" {
+		msg.From = s.From
+	}
+	if msg.From == "" {
+		return fmt.Errorf("mail: message has no sender")
+	}
+	if err := msg.render(s.Views); err != nil {
+		return err
+	}
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	body, err := buildMIME(msg)
+	if err != nil {
+		return err
+	}
+
+	port := s.Port
+	if port == 0 {
+		port = 587
+	}
+	var auth smtp.Auth
+	if s.Username != "" {
+		auth = smtp.PlainAuth("", s.Username, s.Password, s.Host)
+	}
+	addr := net.JoinHostPort(s.Host, fmt.Sprint(port))
+	return smtp.SendMail(addr, auth, msg.From, msg.Recipients(), body)
+}
+
+func buildMIME(msg Message) ([]byte, error) {
+	var b bytes.Buffer
+	header := func(k, v string) {
+		fmt.Fprintf(&b, "%s: %s\r\n", k, v)
+	}
+
+	header("From", msg.From)
+	header("To", strings.Join(msg.To, ", "))
+	if len(msg.Cc) > 0 {
+		header("Cc", strings.Join(msg.Cc, ", "))
+	}
+	if msg.ReplyTo != "" {
+		header("Reply-To", msg.ReplyTo)
+	}
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+
+	if msg.Text == "" || msg.HTML == "" {
+		contentType, content := "text/plain", msg.Text
+		if msg.HTML != "" {
+			contentType, content = "text/html", msg.HTML
+		}
+		if err := writePart(&b, contentType, content); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	}
+
+	boundary, err := newBoundary()
+	if err != nil {
+		return nil, err
+	}
+	header("Content-Type", `multipart/alternative; boundary="`+boundary+`"`)
+	b.WriteString("\r\n")
+	for _, part := range []struct{ typ, content string }{
+		{"text/plain", msg.Text},
+		{"text/html", msg.HTML},
+	} {
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		if err := writePart(&b, part.typ, part.content); err != nil {
+			return nil, err
+		}
+	}
+	fmt.Fprintf(&b, "--%s--\r\n", boundary)
+	return b.Bytes(), nil
+}
+
+func writePart(b *bytes.Buffer, contentType, content string) error {
+	fmt.Fprintf(b, "Content-Type: %s; charset=utf-8\r\n", contentType)
+	b.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+	qp := quotedprintable.NewWriter(b)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	if err := qp.Close(); err != nil {
+		return err
+	}
+	b.WriteString("\r\n")
+	return nil
+}
+
+func newBoundary() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "jimo-" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,151 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+
+	jimohttp "github.com/jimo-go/framework/http"
+)
+
+// View returns a message whose HTML body is rendered from the named template when
+// it is sent, by the Views renderer of the mailer that delivers it. The plain-text
+// alternative is generated from the HTML (see HTMLToText) unless Text is set.
+func View(name string, data any) Message {
+	return Message{Template: name, Data: data}
+}
+
+// render fills m.HTML (and m.Text, if empty) from m.Template using r.
+func (m *Message) render(r jimohttp.Renderer) error {
+	if m.Template == "" {
+		return nil
+	}
+	if r == nil {
+		return fmt.Errorf("mail: no view renderer configured for template %q", m.Template)
+	}
+
+	var buf bytes.Buffer
+	if err := r.Render(&buf, m.Template, m.Data); err != nil {
+		return err
+	}
+	m.HTML = buf.String()
+	if m.Text == "" {
+		m.Text = HTMLToText(m.HTML)
+	}
+	return nil
+}
+
+// HTMLToText converts an HTML email body into a readable plain-text alternative.
+//
+// Block elements become line breaks, list items are bulleted, links keep their
+// target in parentheses, and head/style/script content is dropped.
+func HTMLToText(s string) string {
+	var b strings.Builder
+	var href string
+	skip := ""
+
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			if skip == "" {
+				b.WriteString(s)
+			}
+			break
+		}
+		if skip == "" {
+			b.WriteString(s[:i])
+		}
+		s = s[i:]
+
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			break
+		}
+		tag := s[1:end]
+		s = s[end+1:]
+
+		closing := strings.HasPrefix(tag, "/")
+		name := strings.ToLower(strings.TrimPrefix(tag, "/"))
+		if j := strings.IndexAny(name, " \t\r\n/"); j >= 0 {
+			name = name[:j]
+		}
+
+		if skip != "" {
+			if closing && name == skip {
+				skip = ""
+			}
+			continue
+		}
+
+		switch name {
+		case "head", "style", "script", "title":
+			if !closing {
+				skip = name
+			}
+		case "br":
+			b.WriteString("\n")
+		case "p", "div", "table", "tr", "ul", "ol", "h1", "h2", "h3", "h4", "h5", "h6", "hr", "blockquote":
+			b.WriteString("\n\n")
+		case "li":
+			if !closing {
+				b.WriteString("\n- ")
+			}
+		case "td", "th":
+			if closing {
+				b.WriteString(" ")
+			}
+		case "a":
+			if closing {
+				if href != "" {
+					b.WriteString(" (" + href + ")")
+				}
+				href = ""
+			} else {
+				href = attr(tag, "href")
+			}
+		}
+	}
+
+	// Collapse whitespace within lines and limit blank lines to one.
+	lines := strings.Split(html.UnescapeString(b.String()), "\n")
+	out := make([]string, 0, len(lines))
+	blank := true
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			if !blank {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		out = append(out, line)
+		blank = false
+	}
+	return strings.TrimSpace(strings.Join(out, "\n"))
+}
+
+// attr extracts a quoted attribute value from a raw tag.
+func attr(tag, name string) string {
+	lower := strings.ToLower(tag)
+	i := strings.Index(lower, name+"=")
+	if i < 0 {
+		return ""
+	}
+	rest := tag[i+len(name)+1:]
+	if rest == "" {
+		return ""
+	}
+	quote := rest[0]
+	if quote != '"' && quote != '\'' {
+		if j := strings.IndexAny(rest, " \t\r\n"); j >= 0 {
+			return rest[:j]
+		}
+		return rest
+	}
+	if j := strings.IndexByte(rest[1:], quote); j >= 0 {
+		return rest[1 : j+1]
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package notifications
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/jimo-go/framework/mail"
+)
+
+// MailNotification is implemented by notifications delivered on the "mail" channel.
+type MailNotification interface {
+	ToMail(n Notifiable) (mail.Message, error)
+}
+
+// LogNotification is implemented by notifications delivered on the "log" channel.
+type LogNotification interface {
+	ToLog(n Notifiable) string
+}
+
+// MailChannel sends notifications with the default mailer (mail.Send).
+//
+// Messages without recipients are addressed to RouteNotificationFor("mail").
+type MailChannel struct{}
+
+func (MailChannel) Send(n Notifiable, notification Notification) error {
+	m, ok := notification.(MailNotification)
+	if !ok {
+		return fmt.Errorf("%T does not implement ToMail", notification)
+	}
+	msg, err := m.ToMail(n)
+	if err != nil {
+		return err
+	}
+	if len(msg.Recipients()) == 0 {
+		to := n.RouteNotificationFor("mail")
+		if to == "" {
+			return fmt.Errorf("%T has no mail address", n)
+		}
+		msg.To = []string{to}
+	}
+	return mail.Send(msg)
+}
+
+// LogChannel writes notifications to the standard logger.
+type LogChannel struct{}
+
+func (LogChannel) Send(n Notifiable, notification Notification) error {
+	l, ok := notification.(LogNotification)
+	if !ok {
+		return fmt.Errorf("%T does not implement ToLog", notification)
+	}
+	log.Printf("notification %T to %s: %s", notification, n.RouteNotificationFor("log"), l.ToLog(n))
+	return nil
+}
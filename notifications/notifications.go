@@ -0,0 +1,122 @@
+// Package notifications delivers short messages to users over one or more channels.
+//
+// A notification declares its channels with Via and implements a render method per
+// channel (ToMail, ToLog, ...). Send dispatches it to each channel:
+//
+//	type InvoicePaid struct{ Invoice models.Invoice }
+//
+//	func (InvoicePaid) Via(notifications.Notifiable) []string { return []string{"mail", "log"} }
+//
+//	func (n InvoicePaid) ToMail(notifications.Notifiable) (mail.Message, error) {
+//		return mail.View("emails/invoice_paid", n.Invoice), nil
+//	}
+//
+//	func (n InvoicePaid) ToLog(notifications.Notifiable) string { return "invoice paid" }
+//
+//	err := notifications.Send(user, InvoicePaid{Invoice: inv})
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Notifiable is the recipient of a notification, typically a user model.
+type Notifiable interface {
+	// RouteNotificationFor returns the address for a channel, e.g. an email for "mail".
+	RouteNotificationFor(channel string) string
+}
+
+// Notification declares the channels it is delivered on.
+type Notification interface {
+	Via(n Notifiable) []string
+}
+
+// ShouldQueue marks notifications that should be delivered asynchronously.
+//
+// They are handed to the dispatcher configured with UseQueue, or sent synchronously
+// when none is configured.
+type ShouldQueue interface {
+	ShouldQueue() bool
+}
+
+// Channel delivers notifications of one kind.
+type Channel interface {
+	Send(n Notifiable, notification Notification) error
+}
+
+// ChannelFunc adapts a function to a Channel.
+type ChannelFunc func(n Notifiable, notification Notification) error
+
+func (f ChannelFunc) Send(n Notifiable, notification Notification) error {
+	return f(n, notification)
+}
+
+// Dispatcher runs a delivery job asynchronously, e.g. by pushing it onto a queue.
+type Dispatcher func(job func() error) error
+
+var (
+	mu       sync.RWMutex
+	channels = map[string]Channel{
+		"mail": MailChannel{},
+		"log":  LogChannel{},
+	}
+	dispatcher Dispatcher
+)
+
+// Register adds or replaces the channel with the given name.
+func Register(name string, ch Channel) {
+	mu.Lock()
+	defer mu.Unlock()
+	channels[name] = ch
+}
+
+// UseQueue sets the dispatcher used for notifications implementing ShouldQueue,
+// typically the queue package:
+//
+//	notifications.UseQueue(queue.Push)
+func UseQueue(d Dispatcher) {
+	mu.Lock()
+	defer mu.Unlock()
+	dispatcher = d
+}
+
+// Send delivers notification to n on every channel returned by Via.
+//
+// Delivery continues when a channel fails; the returned error joins every failure.
+// Notifications implementing ShouldQueue are dispatched through UseQueue instead,
+// and Send only reports dispatch errors.
+func Send(n Notifiable, notification Notification) error {
+	if n == nil || notification == nil {
+		return errors.New("notifications: notifiable and notification are required")
+	}
+
+	if q, ok := notification.(ShouldQueue); ok && q.ShouldQueue() {
+		mu.RLock()
+		d := dispatcher
+		mu.RUnlock()
+		if d != nil {
+			return d(func() error { return SendNow(n, notification) })
+		}
+	}
+	return SendNow(n, notification)
+}
+
+// SendNow delivers notification synchronously, ignoring ShouldQueue.
+func SendNow(n Notifiable, notification Notification) error {
+	var errs []error
+	for _, name := range notification.Via(n) {
+		mu.RLock()
+		ch := channels[name]
+		mu.RUnlock()
+		if ch == nil {
+			errs = append(errs, fmt.Errorf("notifications: unknown channel %q", name))
+			continue
+		}
+		if err := ch.Send(n, notification); err != nil {
+			errs = append(errs, fmt.Errorf("notifications: %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
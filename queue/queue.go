@@ -0,0 +1,156 @@
+// Package queue runs jobs outside of the request that created them.
+//
+// The default queue runs jobs synchronously; applications opt into background
+// processing with Use(queue.NewWorkers(...)).
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Job is a unit of background work.
+type Job = func() error
+
+// Queue accepts jobs for execution.
+type Queue interface {
+	Push(job Job) error
+}
+
+var (
+	defaultQueue Queue = Sync{}
+	defaultMu    sync.RWMutex
+)
+
+// Use sets the default queue used by Push. A nil queue restores Sync.
+func Use(q Queue) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if q == nil {
+		q = Sync{}
+	}
+	defaultQueue = q
+}
+
+// Default returns the currently configured default queue.
+func Default() Queue {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultQueue
+}
+
+// Push adds a job to the default queue.
+func Push(job Job) error {
+	return Default().Push(job)
+}
+
+// Sync runs jobs immediately in the caller's goroutine.
+type Sync struct{}
+
+func (Sync) Push(job Job) error {
+	if job == nil {
+		return errors.New("queue: job is nil")
+	}
+	return run(job)
+}
+
+// ErrClosed is returned when pushing to a closed queue.
+var ErrClosed = errors.New("queue: closed")
+
+// Workers is an in-memory queue processed by a fixed pool of goroutines.
+//
+// Jobs are started in the order they were pushed; with a single worker they also
+// finish in that order. Pending jobs are lost if the process exits before Close.
+type Workers struct {
+	// OnError is called with job errors and recovered panics (log.Printf if nil).
+	OnError func(error)
+
+	jobs    chan Job
+	done    chan struct{} // closed by Close to release blocked pushes
+	wg      sync.WaitGroup
+	pushing sync.WaitGroup // pushes in flight, waited on before closing jobs
+	mu      sync.RWMutex
+	closed  bool
+}
+
+// NewWorkers starts n workers (at least one) reading from a queue buffering up to size jobs.
+//
+// Push blocks while the buffer is full, until a worker frees a slot or Close is called.
+func NewWorkers(n, size int) *Workers {
+	if n < 1 {
+		n = 1
+	}
+	if size < 0 {
+		size = 0
+	}
+	w := &Workers{jobs: make(chan Job, size), done: make(chan struct{})}
+	w.wg.Add(n)
+	for i := 0; i < n; i++ {
+		go w.work()
+	}
+	return w
+}
+
+func (w *Workers) Push(job Job) error {
+	if job == nil {
+		return errors.New("queue: job is nil")
+	}
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return ErrClosed
+	}
+	w.pushing.Add(1)
+	w.mu.RUnlock()
+	defer w.pushing.Done()
+
+	select {
+	case w.jobs <- job:
+		return nil
+	case <-w.done:
+		return ErrClosed
+	}
+}
+
+// Close stops accepting jobs and waits for pending ones to finish. Pushes blocked
+// on a full buffer return ErrClosed.
+func (w *Workers) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.done)
+	w.mu.Unlock()
+
+	w.pushing.Wait()
+	close(w.jobs)
+	w.wg.Wait()
+	return nil
+}
+
+func (w *Workers) work() {
+	defer w.wg.Done()
+	for job := range w.jobs {
+		if err := run(job); err != nil {
+			if w.OnError != nil {
+				w.OnError(err)
+			} else {
+				log.Printf("queue: %v", err)
+			}
+		}
+	}
+}
+
+// run executes job, converting a panic into an error.
+func run(job Job) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("queue: job panicked: %v", rec)
+		}
+	}()
+	return job()
+}
@@ -0,0 +1,72 @@
+package queue
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWorkersRunJobs(t *testing.T) {
+	w := NewWorkers(4, 8)
+	var n atomic.Int64
+	for i := 0; i < 100; i++ {
+		if err := w.Push(func() error { n.Add(1); return nil }); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := n.Load(); got != 100 {
+		t.Fatalf("ran %d jobs, want 100", got)
+	}
+	if err := w.Push(func() error { return nil }); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Push after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestWorkersCloseReleasesBlockedPush(t *testing.T) {
+	w := NewWorkers(1, 0)
+	w.OnError = func(error) {}
+
+	started := make(chan struct{})
+	pushed := make(chan error, 1)
+	// The only worker runs a job that pushes to the full queue, so the inner push
+	// can only return once Close releases it.
+	if err := w.Push(func() error {
+		close(started)
+		pushed <- w.Push(func() error { return nil })
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	<-started
+
+	closed := make(chan struct{})
+	go func() {
+		_ = w.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close deadlocked with a push blocked on the full queue")
+	}
+	if err := <-pushed; !errors.Is(err, ErrClosed) {
+		t.Fatalf("blocked Push = %v, want ErrClosed", err)
+	}
+}
+
+func TestWorkersRecoverPanics(t *testing.T) {
+	w := NewWorkers(1, 1)
+	errs := make(chan error, 1)
+	w.OnError = func(err error) { errs <- err }
+	if err := w.Push(func() error { panic("boom") }); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+	if err := <-errs; err == nil || err.Error() != "queue: job panicked: boom" {
+		t.Fatalf("OnError got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+// Package ratelimit counts hits per key in fixed time windows.
+//
+// The counters live in a Store. The default MemoryStore is per process; behind a
+// load balancer, Use a shared store (Redis and the like) so every instance sees
+// the same counts. The http.RateLimit middleware is built on it.
+package ratelimit
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Store keeps the hit counters.
+//
+// Increment must be atomic: it adds one hit to key and returns the count in the
+// current window and the time left until the window resets. The first hit of a
+// key, or the first after its window expired, starts a new window of the given
+// length with a count of 1.
+type Store interface {
+	Increment(key string, window time.Duration) (count int, ttl time.Duration, err error)
+}
+
+var (
+	defaultStore Store = NewMemoryStore()
+	defaultMu    sync.RWMutex
+)
+
+// Use sets the default store. A nil store restores a new MemoryStore.
+func Use(s Store) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if s == nil {
+		s = NewMemoryStore()
+	}
+	defaultStore = s
+}
+
+// Default returns the currently configured default store.
+func Default() Store {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultStore
+}
+
+// Result is the outcome of a Hit.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int           // hits left in the window
+	Reset     time.Duration // time until the window resets
+}
+
+// Hit records a hit for key in store and reports whether it is within max hits
+// per window.
+func Hit(store Store, key string, max int, window time.Duration) (Result, error) {
+	if store == nil {
+		return Result{}, errors.New("ratelimit: store is nil")
+	}
+	if max < 1 || window <= 0 {
+		return Result{}, errors.New("ratelimit: max and window must be positive")
+	}
+	count, ttl, err := store.Increment(key, window)
+	if err != nil {
+		return Result{}, err
+	}
+	remaining := max - count
+	if remaining < 0 {
+		remaining = 0
+	}
+	return Result{Allowed: count <= max, Limit: max, Remaining: remaining, Reset: ttl}, nil
+}
+
+// MemoryStore is an in-process Store.
+//
+// Each process counts on its own, so with n instances behind a load balancer a
+// client gets up to n times the limit.
+type MemoryStore struct {
+	mu      sync.Mutex
+	windows map[string]memoryWindow
+	sweep   time.Time // next time expired windows are dropped
+}
+
+type memoryWindow struct {
+	count   int
+	expires time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{windows: make(map[string]memoryWindow)}
+}
+
+func (s *MemoryStore) Increment(key string, window time.Duration) (int, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.After(s.sweep) {
+		// Opportunistically drop expired windows, at most once a minute.
+		for k, w := range s.windows {
+			if !now.Before(w.expires) {
+				delete(s.windows, k)
+			}
+		}
+		s.sweep = now.Add(time.Minute)
+	}
+
+	w, ok := s.windows[key]
+	if !ok || !now.Before(w.expires) {
+		w = memoryWindow{expires: now.Add(window)}
+	}
+	w.count++
+	s.windows[key] = w
+	return w.count, w.expires.Sub(now), nil
+}
@@ -0,0 +1,50 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHit(t *testing.T) {
+	s := NewMemoryStore()
+	for i, want := range []Result{
+		{Allowed: true, Limit: 2, Remaining: 1},
+		{Allowed: true, Limit: 2, Remaining: 0},
+		{Allowed: false, Limit: 2, Remaining: 0},
+	} {
+		res, err := Hit(s, "k", 2, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Allowed != want.Allowed || res.Limit != want.Limit || res.Remaining != want.Remaining {
+			t.Errorf("hit %d: got %+v, want %+v", i+1, res, want)
+		}
+		if res.Reset <= 0 || res.Reset > time.Minute {
+			t.Errorf("hit %d: reset %v", i+1, res.Reset)
+		}
+	}
+
+	if res, _ := Hit(s, "other", 2, time.Minute); res.Remaining != 1 {
+		t.Errorf("other key shares the counter: %+v", res)
+	}
+}
+
+func TestMemoryStoreWindowExpires(t *testing.T) {
+	s := NewMemoryStore()
+	if n, _, _ := s.Increment("k", 10*time.Millisecond); n != 1 {
+		t.Fatalf("first: %d", n)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n, _, _ := s.Increment("k", 10*time.Millisecond); n != 1 {
+		t.Fatalf("after expiry: %d, want a new window", n)
+	}
+}
+
+func TestHitRejectsInvalidArguments(t *testing.T) {
+	if _, err := Hit(nil, "k", 1, time.Minute); err == nil {
+		t.Error("nil store: no error")
+	}
+	if _, err := Hit(NewMemoryStore(), "k", 0, time.Minute); err == nil {
+		t.Error("zero max: no error")
+	}
+}
@@ -0,0 +1,90 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jimo-go/framework/database"
+)
+
+// UseDatabase registers the unique and exists rules backed by conn:
+//
+//	unique:table,column  fails when a row with column == value already exists
+//	exists:table,column  fails when no row with column == value exists
+//
+// The column defaults to the field name. Empty values are skipped.
+func UseDatabase(conn database.Connection) {
+	Extend("unique", func(field string, value any, arg string) string {
+		found, ok := lookupRow(conn, field, value, arg)
+		if !ok {
+			return fmt.Sprintf("%s could not be verified", field)
+		}
+		if found {
+			return fmt.Sprintf("%s has already been taken", field)
+		}
+		return ""
+	})
+	Extend("exists", func(field string, value any, arg string) string {
+		if isBlank(value) {
+			return ""
+		}
+		found, ok := lookupRow(conn, field, value, arg)
+		if !ok {
+			return fmt.Sprintf("%s could not be verified", field)
+		}
+		if !found {
+			return fmt.Sprintf("%s does not exist", field)
+		}
+		return ""
+	})
+}
+
+// lookupRow reports whether table contains a row whose column matches value.
+//
+// The second result is false when the lookup itself failed.
+func lookupRow(conn database.Connection, field string, value any, arg string) (found bool, ok bool) {
+	if conn == nil {
+		return false, false
+	}
+	if isBlank(value) {
+		return false, true
+	}
+
+	table, column, _ := strings.Cut(arg, ",")
+	table = strings.TrimSpace(table)
+	column = strings.TrimSpace(column)
+	if table == "" {
+		return false, false
+	}
+	if column == "" {
+		column = field
+	}
+
+	if f, ok := conn.(database.Filterer); ok {
+		rows, err := f.Filter(table, "", []database.Condition{{Column: column, Op: database.OpEq, Value: value}})
+		if err != nil {
+			return false, false
+		}
+		return len(rows) > 0, true
+	}
+
+	rows, err := conn.All(table)
+	if err != nil {
+		return false, false
+	}
+	want := fmt.Sprint(value)
+	for _, row := range rows {
+		if v, exists := row[column]; exists && v != nil && fmt.Sprint(v) == want {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+func isBlank(value any) bool {
+	if value == nil {
+		return true
+	}
+	s, ok := value.(string)
+	return ok && strings.TrimSpace(s) == ""
+}
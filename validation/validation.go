@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type Error struct {
@@ -22,6 +23,36 @@ func (e Error) FieldErrors() map[string]string {
 
 type Rules map[string]string
 
+// RuleFunc is a custom validation rule.
+//
+// It receives the field name, the field value and the rule argument (the text after
+// ':'), and returns an error message, or "" when the value passes.
+type RuleFunc func(field string, value any, arg string) string
+
+var (
+	customMu sync.RWMutex
+	custom   = make(map[string]RuleFunc)
+)
+
+// Extend registers a custom rule under name.
+//
+// Built-in rules cannot be overridden; registering the same custom name again replaces it.
+func Extend(name string, fn RuleFunc) {
+	if fn == nil {
+		return
+	}
+	customMu.Lock()
+	defer customMu.Unlock()
+	custom[name] = fn
+}
+
+func customRule(name string) (RuleFunc, bool) {
+	customMu.RLock()
+	defer customMu.RUnlock()
+	fn, ok := custom[name]
+	return fn, ok
+}
+
 func Validate(v any, rules Rules) (Error, bool) {
 	fields := make(map[string]string)
 
@@ -41,14 +72,24 @@ func Validate(v any, rules Rules) (Error, bool) {
 		}
 
 		name := fieldName(f)
-		ruleStr, ok := rules[name]
-		if !ok {
-			continue
+		if ruleStr, ok := rules[name]; ok {
+			if msg := applyRule(rv, rv.Field(i), name, ruleStr); msg != "" {
+				fields[name] = msg
+			}
 		}
 
-		msg := applyRule(rv.Field(i), name, ruleStr)
-		if msg != "" {
-			fields[name] = msg
+		// Element rules: "tags.*" applies to every item of the tags slice.
+		if elemRules, ok := rules[name+".*"]; ok {
+			items := indirect(rv.Field(i))
+			if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+				continue
+			}
+			for j := 0; j < items.Len(); j++ {
+				key := name + "." + strconv.Itoa(j)
+				if msg := applyRule(rv, items.Index(j), key, elemRules); msg != "" {
+					fields[key] = msg
+				}
+			}
 		}
 	}
 
@@ -69,7 +110,25 @@ func fieldName(f reflect.StructField) string {
 	return strings.ToLower(f.Name)
 }
 
-func applyRule(v reflect.Value, name string, ruleStr string) string {
+// sibling returns the field of the struct value parent with the given (tag) name.
+//
+// It returns the zero reflect.Value when no such field exists.
+func sibling(parent reflect.Value, name string) reflect.Value {
+	name = strings.TrimSpace(name)
+	rt := parent.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		if fieldName(f) == name {
+			return parent.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+func applyRule(parent, v reflect.Value, name string, ruleStr string) string {
 	parts := strings.Split(ruleStr, "|")
 	for _, p := range parts {
 		p = strings.TrimSpace(p)
@@ -83,6 +142,20 @@ func applyRule(v reflect.Value, name string, ruleStr string) string {
 			if isEmpty(v) {
 				return fmt.Sprintf("%s is required", name)
 			}
+		case "required_if":
+			other, want, _ := strings.Cut(arg, ",")
+			if asString(sibling(parent, other)) == strings.TrimSpace(want) && isEmpty(v) {
+				return fmt.Sprintf("%s is required", name)
+			}
+		case "required_unless":
+			other, want, _ := strings.Cut(arg, ",")
+			if asString(sibling(parent, other)) != strings.TrimSpace(want) && isEmpty(v) {
+				return fmt.Sprintf("%s is required", name)
+			}
+		case "required_with":
+			if !isEmpty(sibling(parent, arg)) && isEmpty(v) {
+				return fmt.Sprintf("%s is required", name)
+			}
 		case "email":
 			s := asString(v)
 			if s == "" {
@@ -91,8 +164,40 @@ func applyRule(v reflect.Value, name string, ruleStr string) string {
 			if _, err := mail.ParseAddress(s); err != nil {
 				return fmt.Sprintf("%s must be a valid email", name)
 			}
+		case "in":
+			s := asString(v)
+			if s == "" {
+				continue
+			}
+			allowed := splitArgs(arg)
+			if !contains(allowed, s) {
+				return fmt.Sprintf("%s must be one of: %s", name, strings.Join(allowed, ", "))
+			}
+		case "not_in":
+			s := asString(v)
+			if s == "" {
+				continue
+			}
+			denied := splitArgs(arg)
+			if contains(denied, s) {
+				return fmt.Sprintf("%s must not be one of: %s", name, strings.Join(denied, ", "))
+			}
+		case "boolean":
+			if !isBoolean(v) {
+				return fmt.Sprintf("%s must be true or false", name)
+			}
+		case "array":
+			if k := indirect(v).Kind(); k != reflect.Slice && k != reflect.Array && !isEmpty(v) {
+				return fmt.Sprintf("%s must be an array", name)
+			}
 		case "min":
 			n, _ := strconv.Atoi(arg)
+			if l, ok := length(v); ok {
+				if l < n {
+					return fmt.Sprintf("%s must have at least %d items", name, n)
+				}
+				continue
+			}
 			if n > 0 {
 				if len(asString(v)) < n {
 					return fmt.Sprintf("%s must be at least %d characters", name, n)
@@ -100,16 +205,103 @@ func applyRule(v reflect.Value, name string, ruleStr string) string {
 			}
 		case "max":
 			n, _ := strconv.Atoi(arg)
+			if l, ok := length(v); ok {
+				if l > n {
+					return fmt.Sprintf("%s must have at most %d items", name, n)
+				}
+				continue
+			}
 			if n > 0 {
 				if len(asString(v)) > n {
 					return fmt.Sprintf("%s must be at most %d characters", name, n)
 				}
 			}
+		default:
+			if fn, ok := customRule(key); ok {
+				if msg := fn(name, valueOf(v), arg); msg != "" {
+					return msg
+				}
+			}
 		}
 	}
 	return ""
 }
 
+func splitArgs(arg string) []string {
+	parts := strings.Split(arg, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// isBoolean accepts bools, 0/1 integers, and the strings true/false/1/0/on/off.
+func isBoolean(v reflect.Value) bool {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return true
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return true
+	case reflect.String:
+		switch strings.ToLower(strings.TrimSpace(v.String())) {
+		case "", "true", "false", "1", "0", "on", "off":
+			return true
+		}
+		return false
+	default:
+		s := asString(v)
+		return s == "0" || s == "1"
+	}
+}
+
+// valueOf returns the underlying value of v, dereferencing pointers (nil for nil pointers).
+func valueOf(v reflect.Value) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+func indirect(v reflect.Value) reflect.Value {
+	if v.IsValid() && v.Kind() == reflect.Pointer && !v.IsNil() {
+		return v.Elem()
+	}
+	return v
+}
+
+// length returns the number of items in a slice, array or map value.
+func length(v reflect.Value) (int, bool) {
+	v = indirect(v)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return v.Len(), true
+	default:
+		return 0, false
+	}
+}
+
 func asString(v reflect.Value) string {
 	if !v.IsValid() {
 		return ""
@@ -127,6 +319,8 @@ func asString(v reflect.Value) string {
 		return strconv.FormatInt(v.Int(), 10)
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
 	default:
 		return ""
 	}
@@ -148,6 +342,8 @@ func isEmpty(v reflect.Value) bool {
 		return v.Int() == 0
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		return v.Uint() == 0
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
 	default:
 		z := reflect.Zero(v.Type())
 		return reflect.DeepEqual(v.Interface(), z.Interface())
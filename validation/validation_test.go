@@ -0,0 +1,58 @@
+package validation
+
+import "testing"
+
+type post struct {
+	Status   string `json:"status"`
+	Priority int    `json:"priority"`
+	Slug     string `json:"slug"`
+	Draft    string `json:"draft"`
+	Pinned   int    `json:"pinned"`
+}
+
+func TestInNotInAndBoolean(t *testing.T) {
+	rules := Rules{
+		"status":   "in:draft,published",
+		"priority": "in:1, 2, 3",
+		"slug":     "not_in:admin,login",
+		"draft":    "boolean",
+		"pinned":   "boolean",
+	}
+
+	valid := post{Status: "draft", Priority: 2, Slug: "hello", Draft: "on", Pinned: 1}
+	if errs, failed := Validate(valid, rules); failed {
+		t.Fatalf("valid post failed: %v", errs.Fields)
+	}
+
+	invalid := post{Status: "archived", Priority: 7, Slug: "admin", Draft: "yes", Pinned: 2}
+	errs, failed := Validate(invalid, rules)
+	if !failed {
+		t.Fatal("invalid post passed")
+	}
+	want := map[string]string{
+		"status":   "status must be one of: draft, published",
+		"priority": "priority must be one of: 1, 2, 3",
+		"slug":     "slug must not be one of: admin, login",
+		"draft":    "draft must be true or false",
+		"pinned":   "pinned must be true or false",
+	}
+	for field, msg := range want {
+		if errs.Fields[field] != msg {
+			t.Errorf("%s: got %q, want %q", field, errs.Fields[field], msg)
+		}
+	}
+}
+
+func TestInSkipsEmptyStrings(t *testing.T) {
+	if errs, failed := Validate(post{}, Rules{"status": "in:draft,published", "slug": "not_in:admin"}); failed {
+		t.Fatalf("empty values failed: %v", errs.Fields)
+	}
+}
+
+func TestBooleanStrings(t *testing.T) {
+	for _, s := range []string{"true", "FALSE", "1", "0", "on", "Off", ""} {
+		if errs, failed := Validate(post{Draft: s}, Rules{"draft": "boolean"}); failed {
+			t.Errorf("%q rejected: %v", s, errs.Fields)
+		}
+	}
+}